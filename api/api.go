@@ -0,0 +1,9 @@
+// Package api 保存接口定义，.thrift 源码随程序一起编译，供反射服务返回
+package api
+
+import "embed"
+
+// IDL api 目录下的 .thrift 文件
+//
+//go:embed *.thrift
+var IDL embed.FS
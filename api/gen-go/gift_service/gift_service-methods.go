@@ -0,0 +1,31 @@
+// Code generated by thrift-gen-methods. DO NOT EDIT.
+// source: gift_service.thrift
+
+package gift_service
+
+import (
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+// GiftServiceMethods GiftService 各方法的参数与结果结构体，键为 IDL 中的方法名
+var GiftServiceMethods = map[string]struct {
+	Args   func() thrift.TStruct
+	Result func() thrift.TStruct
+}{
+	"SendGift": {
+		Args:   func() thrift.TStruct { return NewGiftServiceSendGiftArgs() },
+		Result: func() thrift.TStruct { return NewGiftServiceSendGiftResult() },
+	},
+	"GetTop10Senders": {
+		Args:   func() thrift.TStruct { return NewGiftServiceGetTop10SendersArgs() },
+		Result: func() thrift.TStruct { return NewGiftServiceGetTop10SendersResult() },
+	},
+	"GetSendersInLastWeek": {
+		Args:   func() thrift.TStruct { return NewGiftServiceGetSendersInLastWeekArgs() },
+		Result: func() thrift.TStruct { return NewGiftServiceGetSendersInLastWeekResult() },
+	},
+	"GetGiftsBySender": {
+		Args:   func() thrift.TStruct { return NewGiftServiceGetGiftsBySenderArgs() },
+		Result: func() thrift.TStruct { return NewGiftServiceGetGiftsBySenderResult() },
+	},
+}
@@ -0,0 +1,27 @@
+// Code generated by thrift-gen-methods. DO NOT EDIT.
+// source: reflection.thrift
+
+package reflection
+
+import (
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+// ReflectionMethods Reflection 各方法的参数与结果结构体，键为 IDL 中的方法名
+var ReflectionMethods = map[string]struct {
+	Args   func() thrift.TStruct
+	Result func() thrift.TStruct
+}{
+	"ListServices": {
+		Args:   func() thrift.TStruct { return NewReflectionListServicesArgs() },
+		Result: func() thrift.TStruct { return NewReflectionListServicesResult() },
+	},
+	"DescribeService": {
+		Args:   func() thrift.TStruct { return NewReflectionDescribeServiceArgs() },
+		Result: func() thrift.TStruct { return NewReflectionDescribeServiceResult() },
+	},
+	"GetFileSource": {
+		Args:   func() thrift.TStruct { return NewReflectionGetFileSourceArgs() },
+		Result: func() thrift.TStruct { return NewReflectionGetFileSourceResult() },
+	},
+}
@@ -0,0 +1,19 @@
+// Code generated by thrift-gen-methods. DO NOT EDIT.
+// source: user_service.thrift
+
+package user_service
+
+import (
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+// UserServiceMethods UserService 各方法的参数与结果结构体，键为 IDL 中的方法名
+var UserServiceMethods = map[string]struct {
+	Args   func() thrift.TStruct
+	Result func() thrift.TStruct
+}{
+	"echoData": {
+		Args:   func() thrift.TStruct { return NewUserServiceEchoDataArgs() },
+		Result: func() thrift.TStruct { return NewUserServiceEchoDataResult() },
+	},
+}
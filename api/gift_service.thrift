@@ -18,14 +18,14 @@ struct Gift {
 
 service GiftService {
   // 送礼操作：发送礼物
-  Gift SendGift(1: i64 senderId, 2: i64 receiverId, 3: i32 price, 4: GiftType giftType, 5: i32 quantity),
+  Gift SendGift(1: i64 senderId, 2: i64 receiverId, 3: i32 price, 4: GiftType giftType, 5: i32 quantity) (api.post = "/v1/gifts"),
 
   // 查询送礼最多的前10人（按累计送礼金额排序）
-  list<i64> GetTop10Senders(),
+  list<i64> GetTop10Senders() (api.get = "/v1/senders/top10"),
 
   // 查询一周内送礼的名单（返回送礼者ID列表，去重）
-  list<i64> GetSendersInLastWeek(),
+  list<i64> GetSendersInLastWeek() (api.get = "/v1/senders/last-week"),
 
   // 查询指定某人所有送礼记录，返回结构体列表
-  list<Gift> GetGiftsBySender(1: i64 senderId),
+  list<Gift> GetGiftsBySender(1: i64 senderId) (api.get = "/v1/senders/{senderId}/gifts"),
 }
\ No newline at end of file
@@ -46,5 +46,5 @@ struct EchoResponse {
 }
 
 service UserService {
-  EchoResponse echoData(1: binary clientData, 2: User user),
+  EchoResponse echoData(1: binary clientData, 2: User user) (api.post = "/v1/echo"),
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"os"
 
@@ -12,7 +13,14 @@ import (
 	"github.com/go-kratos/kratos/v2/config/file"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/middleware/tracing"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	"github.com/go-kratos/kratos/v2/transport/http"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 
 	_ "go.uber.org/automaxprocs"
 )
@@ -33,8 +41,8 @@ func init() {
 	flag.StringVar(&flagconf, "conf", "../../configs", "config path, eg: -conf config.yaml")
 }
 
-func newApp(logger log.Logger, ts *server.ThriftServer) *kratos.App {
-	return kratos.New(
+func newApp(logger log.Logger, ts *server.ThriftServer, hs *http.Server, gs *grpc.Server, rr registry.Registrar) *kratos.App {
+	opts := []kratos.Option{
 		kratos.ID(id),
 		kratos.Name(Name),
 		kratos.Version(Version),
@@ -42,8 +50,15 @@ func newApp(logger log.Logger, ts *server.ThriftServer) *kratos.App {
 		kratos.Logger(logger),
 		kratos.Server(
 			ts,
+			hs,
+			gs,
 		),
-	)
+	}
+	// 配置了注册中心时启动后注册 thrift 地址，停止时注销
+	if rr != nil {
+		opts = append(opts, kratos.Registrar(rr))
+	}
+	return kratos.New(opts...)
 }
 
 func main() {
@@ -58,6 +73,19 @@ func main() {
 		"span.id", tracing.SpanID(),
 	)
 	logrus.SetReportCaller(true)
+
+	// 全局 TracerProvider，thrift 服务端与客户端、Redis 命令的 span 都由它创建，
+	// 需要上报到 collector 时在这里注册 exporter
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.AlwaysSample())),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceNameKey.String(Name),
+			semconv.ServiceVersionKey.String(Version),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+	defer tp.Shutdown(context.Background())
+
 	c := config.New(
 		config.WithSource(
 			file.NewSource(flagconf),
@@ -74,7 +102,7 @@ func main() {
 		panic(err)
 	}
 
-	app, cleanup, err := wireApp(bc.Server, bc.Data, logger)
+	app, cleanup, err := wireApp(bc.Server, bc.Data, bc.Registry, logger)
 	if err != nil {
 		panic(err)
 	}
@@ -10,6 +10,7 @@ import (
 	"aboveThriftRPC/internal/biz"
 	"aboveThriftRPC/internal/conf"
 	"aboveThriftRPC/internal/data"
+	"aboveThriftRPC/internal/pkg/health"
 	"aboveThriftRPC/internal/server"
 	"aboveThriftRPC/internal/service"
 	"github.com/go-kratos/kratos/v2"
@@ -23,7 +24,7 @@ import (
 // Injectors from wire.go:
 
 // wireApp init kratos application.
-func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger) (*kratos.App, func(), error) {
+func wireApp(confServer *conf.Server, confData *conf.Data, registry *conf.Registry, logger log.Logger) (*kratos.App, func(), error) {
 	dataData, cleanup, err := data.NewData(confData)
 	if err != nil {
 		return nil, nil, err
@@ -34,12 +35,24 @@ func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger) (*
 	giftRepo := data.NewGiftRepo(dataData)
 	giftUsecase := biz.NewGiftUsecase(giftRepo)
 	giftService := service.NewThriftGiftService(giftUsecase)
-	thriftServer, err := server.NewThriftServer(confServer, userService, giftService)
+	checks := data.NewHealthChecks(dataData)
+	healthServer := health.NewServer(checks)
+	v := server.NewThriftServerOptions(healthServer)
+	thriftServer, err := server.NewThriftServer(confServer, userService, giftService, v...)
 	if err != nil {
 		cleanup()
 		return nil, nil, err
 	}
-	app := newApp(logger, thriftServer)
+	httpServer := server.NewHTTPServer(confServer, thriftServer, userService, giftService, logger)
+	userServiceServer := service.NewGRPCUserService(userService)
+	giftServiceServer := service.NewGRPCGiftService(giftService)
+	grpcServer := server.NewGRPCServer(confServer, userServiceServer, giftServiceServer, logger)
+	registrar, err := server.NewRegistrar(registry)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	app := newApp(logger, thriftServer, httpServer, grpcServer, registrar)
 	return app, func() {
 		cleanup()
 	}, nil
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strings"
+	"text/template"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+var gatewayTemplate = template.Must(template.New("gateway").Parse(`// Code generated by thrift-gen-http. DO NOT EDIT.
+// source: {{.Source}}
+
+package {{.Package}}
+
+import (
+	context "context"
+
+	errors "github.com/go-kratos/kratos/v2/errors"
+	http "github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the kratos package it is being compiled against.
+var _ = new(context.Context)
+var _ = new(errors.Error)
+
+const _ = http.SupportPackageIsVersion1
+{{range .Services}}
+{{range .Handlers}}const Operation{{.Service}}{{.GoMethod}} = "{{.Operation}}"
+{{end}}
+// Register{{.Name}}HTTPServer 把 {{.Name}} 的方法注册为 HTTP 路由
+func Register{{.Name}}HTTPServer(s *http.Server, srv {{.Name}}) {
+	r := s.Route("/")
+{{- range .Handlers}}
+	r.{{.Method}}("{{.Path}}", _{{.Service}}_{{.GoMethod}}0_HTTP_Handler(srv))
+{{- end}}
+}
+{{range .Handlers}}
+func _{{.Service}}_{{.GoMethod}}0_HTTP_Handler(srv {{.Service}}) func(ctx http.Context) error {
+	return func(ctx http.Context) error {
+		in := New{{.ArgsType}}()
+{{- range .InitArgs}}
+		in.{{.Field}} = New{{.Type}}()
+{{- end}}
+{{- if .HasBody}}
+		if err := ctx.Bind(in); err != nil {
+			return err
+		}
+{{- end}}
+		if err := ctx.BindQuery(in); err != nil {
+			return err
+		}
+{{- if .HasVars}}
+		if err := ctx.BindVars(in); err != nil {
+			return err
+		}
+{{- end}}
+		http.SetOperation(ctx, Operation{{.Service}}{{.GoMethod}})
+		h := ctx.Middleware(func(ctx context.Context, req interface{}) (interface{}, error) {
+{{- if .Args}}
+			args := req.(*{{.ArgsType}})
+{{- end}}
+{{- if .Reply}}
+			return srv.{{.GoMethod}}(ctx{{range .Args}}, args.{{.}}{{end}})
+{{- else}}
+			return nil, srv.{{.GoMethod}}(ctx{{range .Args}}, args.{{.}}{{end}})
+{{- end}}
+		})
+{{- if .Reply}}
+		out, err := h(ctx, in)
+		if err != nil {
+			return err
+		}
+		reply := out.({{.Reply}})
+{{- if .Pointer}}
+		if reply == nil {
+			return errors.InternalServer("MISSING_RESULT", "{{.Function}} failed: unknown result")
+		}
+{{- else if .Empty}}
+		if reply == nil {
+			reply = {{.Empty}}
+		}
+{{- end}}
+		return ctx.Result(200, reply)
+{{- else}}
+		if _, err := h(ctx, in); err != nil {
+			return err
+		}
+		return ctx.Result(200, struct{}{})
+{{- end}}
+	}
+}
+{{end}}{{end}}`))
+
+type gatewayFile struct {
+	Source   string
+	Package  string
+	Services []*gatewayService
+}
+
+type gatewayService struct {
+	Name     string
+	Handlers []*gatewayHandler
+}
+
+type gatewayHandler struct {
+	Service   string
+	Function  string
+	GoMethod  string
+	Operation string
+	Method    string
+	Path      string
+	ArgsType  string
+	Args      []string
+	InitArgs  []gatewayInit
+	HasBody   bool
+	HasVars   bool
+	// Reply 为返回值的 Go 类型，void 时为空；Pointer 表示结构体指针，Empty 为容器的空值
+	Reply   string
+	Pointer bool
+	Empty   string
+}
+
+// gatewayInit 结构体参数先用 NewXxx 初始化，请求中没有的字段保留 IDL 中的默认值
+type gatewayInit struct {
+	Field string
+	Type  string
+}
+
+// generateGo 为 doc 中的每个服务生成 Register{Service}HTTPServer
+func (g *generator) generateGo(doc *thriftidl.Document) ([]byte, error) {
+	file := &gatewayFile{Source: doc.Filename, Package: doc.Namespace("go")}
+	for _, svc := range doc.Services {
+		if svc.Extends != "" {
+			return nil, fmt.Errorf("%s: 暂不支持 extends", svc.Name)
+		}
+		routes, err := g.routes(doc, svc)
+		if err != nil {
+			return nil, err
+		}
+		gs := &gatewayService{Name: svc.Name}
+		for _, r := range routes {
+			h, err := g.handler(doc, r)
+			if err != nil {
+				return nil, err
+			}
+			gs.Handlers = append(gs.Handlers, h)
+		}
+		file.Services = append(file.Services, gs)
+	}
+	var buf bytes.Buffer
+	if err := gatewayTemplate.Execute(&buf, file); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化 %s 生成的代码失败: %w", doc.Filename, err)
+	}
+	return src, nil
+}
+
+func (g *generator) handler(doc *thriftidl.Document, r *route) (*gatewayHandler, error) {
+	f := r.function
+	h := &gatewayHandler{
+		Service:   r.service.Name,
+		Function:  f.Name,
+		GoMethod:  thriftidl.GoName(f.Name),
+		Operation: r.service.Name + "." + f.Name,
+		Method:    r.method,
+		Path:      r.path,
+		ArgsType:  r.service.Name + thriftidl.GoName(f.Name) + "Args",
+		HasBody:   len(r.bodyArgs) > 0,
+		HasVars:   len(r.pathArgs) > 0,
+	}
+	for _, a := range f.Args {
+		h.Args = append(h.Args, thriftidl.GoName(a.Name))
+		if a.Type.IsBase() || a.Type.IsContainer() {
+			continue
+		}
+		ref, err := g.Resolve(doc, a.Type)
+		if err != nil {
+			return nil, err
+		}
+		// 只有直接引用本文件的结构体时才能调用 NewXxx
+		if ref.Struct != nil && ref.Doc == doc && a.Type.Name == ref.Name {
+			h.InitArgs = append(h.InitArgs, gatewayInit{Field: thriftidl.GoName(a.Name), Type: ref.Name})
+		}
+	}
+	if f.Returns == nil {
+		return h, nil
+	}
+	reply, err := g.goType(doc, f.Returns)
+	if err != nil {
+		return nil, fmt.Errorf("%s.%s: %w", r.service.Name, f.Name, err)
+	}
+	h.Reply = reply
+	ref, err := g.Resolve(doc, f.Returns)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ref.Struct != nil:
+		h.Pointer = true
+	case ref.Type.IsContainer() || ref.Type.Name == thriftidl.TypeBinary:
+		h.Empty = reply + "{}"
+	}
+	return h, nil
+}
+
+// goType 返回 thrift go 生成器为类型生成的 Go 类型
+func (g *generator) goType(doc *thriftidl.Document, t *thriftidl.Type) (string, error) {
+	switch t.Name {
+	case thriftidl.TypeBool:
+		return "bool", nil
+	case thriftidl.TypeByte, thriftidl.TypeI8:
+		return "int8", nil
+	case thriftidl.TypeI16:
+		return "int16", nil
+	case thriftidl.TypeI32:
+		return "int32", nil
+	case thriftidl.TypeI64:
+		return "int64", nil
+	case thriftidl.TypeDouble:
+		return "float64", nil
+	case thriftidl.TypeString:
+		return "string", nil
+	case thriftidl.TypeBinary:
+		return "[]byte", nil
+	case thriftidl.TypeList, thriftidl.TypeSet:
+		elem, err := g.goType(doc, t.Value)
+		return "[]" + elem, err
+	case thriftidl.TypeMap:
+		key, err := g.goType(doc, t.Key)
+		if err != nil {
+			return "", err
+		}
+		value, err := g.goType(doc, t.Value)
+		return "map[" + key + "]" + value, err
+	}
+	if strings.Contains(t.Name, ".") {
+		return "", fmt.Errorf("暂不支持引用其他文件的类型 %s", t.Name)
+	}
+	ref, err := g.Resolve(doc, t)
+	if err != nil {
+		return "", err
+	}
+	if ref.Struct != nil {
+		return "*" + t.Name, nil
+	}
+	return t.Name, nil
+}
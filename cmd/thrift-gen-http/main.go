@@ -0,0 +1,180 @@
+// thrift-gen-http 根据 .thrift 文件生成 kratos HTTP 网关与 OpenAPI 文档
+//
+// 方法上的 api.get、api.post、api.put、api.patch、api.delete 注解声明路由，路径中的 {arg} 绑定同名参数；
+// 没有注解的方法映射为 POST /{Service}/{method}。GET、DELETE 其余的参数从 query 绑定，只支持标量类型；
+// 其他请求方法其余的参数从 JSON 请求体绑定。
+//
+//	go run ./cmd/thrift-gen-http -go_out ./api/gen-go -openapi_out ./openapi.yaml api/*.thrift
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+var (
+	goOut      = flag.String("go_out", "", "生成的 Go 代码目录，每个文件写入 {go_out}/{namespace}/{name}-http.go")
+	openapiOut = flag.String("openapi_out", "", "生成的 OpenAPI 文档路径")
+	title      = flag.String("title", "aboveThrift API", "OpenAPI 文档标题")
+	version    = flag.String("version", "0.0.1", "OpenAPI 文档版本")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 || (*goOut == "" && *openapiOut == "") {
+		fmt.Fprintln(os.Stderr, "usage: thrift-gen-http [-go_out dir] [-openapi_out file] file.thrift...")
+		os.Exit(2)
+	}
+	if err := run(flag.Args(), *goOut, *openapiOut); err != nil {
+		fmt.Fprintln(os.Stderr, "thrift-gen-http:", err)
+		os.Exit(1)
+	}
+}
+
+func run(files []string, goOut, openapiOut string) error {
+	g, err := newGenerator(files)
+	if err != nil {
+		return err
+	}
+	if goOut != "" {
+		for _, doc := range g.Docs {
+			if len(doc.Services) == 0 {
+				continue
+			}
+			src, err := g.generateGo(doc)
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(goOut, doc.Namespace("go"), doc.BaseName()+"-http.go")
+			if err := os.WriteFile(path, src, 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	if openapiOut != "" {
+		src, err := g.generateOpenAPI(*title, *version)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(openapiOut, src, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generator 保存全部解析结果
+type generator struct {
+	*thriftidl.Set
+}
+
+func newGenerator(files []string) (*generator, error) {
+	set, err := thriftidl.ParseFiles(files...)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range set.Docs {
+		if doc.Namespace("go") == "" {
+			return nil, fmt.Errorf("%s: 缺少 namespace go", doc.Filename)
+		}
+	}
+	return &generator{set}, nil
+}
+
+// route 一个方法对应的 HTTP 路由
+type route struct {
+	service  *thriftidl.Service
+	function *thriftidl.Function
+	method   string
+	path     string
+	pathArgs []*thriftidl.Field
+	// queryArgs 与 bodyArgs 只有一个非空，取决于请求方法是否有请求体
+	queryArgs []*thriftidl.Field
+	bodyArgs  []*thriftidl.Field
+}
+
+var routeAnnotations = []struct {
+	key    string
+	method string
+}{
+	{"api.get", "GET"},
+	{"api.post", "POST"},
+	{"api.put", "PUT"},
+	{"api.patch", "PATCH"},
+	{"api.delete", "DELETE"},
+}
+
+func (g *generator) routes(doc *thriftidl.Document, svc *thriftidl.Service) ([]*route, error) {
+	var routes []*route
+	for _, f := range svc.Functions {
+		r := &route{service: svc, function: f}
+		for _, a := range routeAnnotations {
+			if path, ok := f.Annotations[a.key]; ok {
+				if r.method != "" {
+					return nil, fmt.Errorf("%s.%s: 只能声明一个路由", svc.Name, f.Name)
+				}
+				r.method, r.path = a.method, path
+			}
+		}
+		if r.method == "" {
+			r.method, r.path = "POST", "/"+svc.Name+"/"+f.Name
+		}
+		if err := g.bindArgs(doc, r); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", svc.Name, f.Name, err)
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+// bindArgs 按路径变量与请求方法把参数分到路径、query 与请求体
+func (g *generator) bindArgs(doc *thriftidl.Document, r *route) error {
+	args := map[string]*thriftidl.Field{}
+	for _, a := range r.function.Args {
+		args[a.Name] = a
+	}
+	inPath := map[string]bool{}
+	for _, seg := range strings.Split(r.path, "/") {
+		if !strings.HasPrefix(seg, "{") || !strings.HasSuffix(seg, "}") {
+			continue
+		}
+		name := seg[1 : len(seg)-1]
+		a, ok := args[name]
+		if !ok {
+			return fmt.Errorf("路径变量 {%s} 没有对应的参数", name)
+		}
+		ref, err := g.Resolve(doc, a.Type)
+		if err != nil {
+			return err
+		}
+		if !ref.IsScalar() {
+			return fmt.Errorf("路径变量 {%s} 必须是标量类型", name)
+		}
+		inPath[name] = true
+		r.pathArgs = append(r.pathArgs, a)
+	}
+	hasBody := r.method != "GET" && r.method != "DELETE"
+	for _, a := range r.function.Args {
+		if inPath[a.Name] {
+			continue
+		}
+		if hasBody {
+			r.bodyArgs = append(r.bodyArgs, a)
+			continue
+		}
+		ref, err := g.Resolve(doc, a.Type)
+		if err != nil {
+			return err
+		}
+		if !ref.IsScalar() {
+			return fmt.Errorf("%s 请求的参数 %s 必须是标量类型", r.method, a.Name)
+		}
+		r.queryArgs = append(r.queryArgs, a)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+// TestGeneratedUpToDate 测试提交的网关代码与 openapi.yaml 与 IDL 保持一致
+func TestGeneratedUpToDate(t *testing.T) {
+	files, err := filepath.Glob("../../api/*.thrift")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("找不到 thrift 文件: %v", err)
+	}
+	out := t.TempDir()
+	for _, f := range files {
+		doc, err := thriftidl.ParseFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(out, doc.Namespace("go")), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := run(files, out, filepath.Join(out, "openapi.yaml")); err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+
+	generated, err := filepath.Glob(filepath.Join(out, "*", "*-http.go"))
+	if err != nil || len(generated) == 0 {
+		t.Fatalf("没有生成网关代码: %v", err)
+	}
+	pairs := map[string]string{filepath.Join(out, "openapi.yaml"): "../../openapi.yaml"}
+	for _, g := range generated {
+		rel, _ := filepath.Rel(out, g)
+		pairs[g] = filepath.Join("../../api/gen-go", rel)
+	}
+	for got, want := range pairs {
+		a, err := os.ReadFile(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s 与 IDL 不一致，请执行 make gateway 重新生成", want)
+		}
+	}
+}
+
+func TestRouteErrors(t *testing.T) {
+	for _, src := range []string{
+		`namespace go demo
+service S { void f(1: i64 id) (api.get = "/v1/{name}") }`,
+		`namespace go demo
+struct A { 1: i64 id }
+service S { void f(1: A a) (api.get = "/v1/f") }`,
+		`namespace go demo
+service S { void f(1: binary data) (api.post = "/v1/{data}") }`,
+		`namespace go demo
+service S { void f() (api.get = "/a", api.post = "/b") }`,
+	} {
+		doc, err := thriftidl.Parse("demo.thrift", []byte(src))
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		g := &generator{thriftidl.NewSet(doc)}
+		if _, err := g.generateGo(doc); err == nil {
+			t.Errorf("期望生成失败: %s", strings.SplitN(src, "\n", 2)[1])
+		}
+	}
+}
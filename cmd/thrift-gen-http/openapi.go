@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+
+	"gopkg.in/yaml.v3"
+)
+
+// object 保持键顺序的 YAML 映射
+type object []member
+
+type member struct {
+	key   string
+	value interface{}
+}
+
+func (o object) MarshalYAML() (interface{}, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode}
+	for _, m := range o {
+		var v yaml.Node
+		if err := v.Encode(m.value); err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: m.key}, &v)
+	}
+	return n, nil
+}
+
+// add 值为空时跳过
+func (o *object) add(key string, value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		return
+	case string:
+		if v == "" {
+			return
+		}
+	case bool:
+		if !v {
+			return
+		}
+	case []string:
+		if len(v) == 0 {
+			return
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			return
+		}
+	case object:
+		if len(v) == 0 {
+			return
+		}
+	}
+	*o = append(*o, member{key, value})
+}
+
+// errorSchema kratos 默认错误响应的结构
+const errorSchema = "errors.Status"
+
+// generateOpenAPI 生成全部服务的 OpenAPI 3 文档
+func (g *generator) generateOpenAPI(title, version string) ([]byte, error) {
+	var (
+		paths   object
+		tags    []interface{}
+		schemas = map[string]interface{}{}
+		order   []string
+		sources []string
+	)
+	// pathItem 同一路径的多个方法合并到一个 path item
+	pathItem := func(path string) *object {
+		for i := range paths {
+			if paths[i].key == path {
+				item := paths[i].value.(*object)
+				return item
+			}
+		}
+		item := &object{}
+		paths = append(paths, member{path, item})
+		return item
+	}
+	addSchema := func(name string, schema object) {
+		if _, ok := schemas[name]; !ok {
+			order = append(order, name)
+		}
+		schemas[name] = schema
+	}
+
+	for _, doc := range g.Docs {
+		sources = append(sources, doc.Filename)
+		for _, svc := range doc.Services {
+			routes, err := g.routes(doc, svc)
+			if err != nil {
+				return nil, err
+			}
+			tag := object{{"name", svc.Name}}
+			tag.add("description", svc.Doc)
+			tags = append(tags, tag)
+			for _, r := range routes {
+				op, err := g.operation(doc, r, addSchema)
+				if err != nil {
+					return nil, err
+				}
+				pathItem(r.path).add(strings.ToLower(r.method), op)
+			}
+		}
+		// 引用到的结构体与枚举按定义顺序输出
+		for _, e := range doc.Enums {
+			addSchema(schemaName(doc, e.Name), enumSchema(e))
+		}
+		for _, s := range doc.Structs {
+			schema, err := g.structSchema(doc, s.Doc, s.Fields)
+			if err != nil {
+				return nil, err
+			}
+			addSchema(schemaName(doc, s.Name), schema)
+		}
+	}
+	addSchema(errorSchema, object{
+		{"type", "object"},
+		{"properties", object{
+			{"code", object{{"type", "integer"}, {"format", "int32"}, {"description", "HTTP 状态码"}}},
+			{"reason", object{{"type", "string"}, {"description", "错误原因"}}},
+			{"message", object{{"type", "string"}, {"description", "错误信息"}}},
+			{"metadata", object{{"type", "object"}, {"additionalProperties", object{{"type", "string"}}}}},
+		}},
+		{"description", "kratos 默认的错误响应"},
+	})
+
+	// 与 protoc-gen-openapi 一样按名字排序
+	sort.Strings(order)
+	components := object{}
+	for _, name := range order {
+		components = append(components, member{name, schemas[name]})
+	}
+	root := object{
+		{"openapi", "3.0.3"},
+		{"info", object{
+			{"title", title},
+			{"description", "由 " + strings.Join(sources, "、") + " 生成的 JSON/REST 网关"},
+			{"version", version},
+		}},
+		{"paths", paths},
+		{"components", object{{"schemas", components}}},
+		{"tags", tags},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("# Generated with thrift-gen-http. DO NOT EDIT.\n")
+	buf.WriteString("# source: " + strings.Join(sources, ", ") + "\n\n")
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func schemaName(doc *thriftidl.Document, name string) string {
+	return doc.Namespace("go") + "." + name
+}
+
+func schemaRef(name string) object {
+	return object{{"$ref", "#/components/schemas/" + name}}
+}
+
+func (g *generator) operation(doc *thriftidl.Document, r *route, addSchema func(string, object)) (object, error) {
+	f := r.function
+	op := object{{"tags", []string{r.service.Name}}}
+	op.add("description", f.Doc)
+	op.add("operationId", r.service.Name+"_"+f.Name)
+
+	var params []interface{}
+	for _, a := range r.pathArgs {
+		p, err := g.parameter(doc, a, "path")
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	}
+	for _, a := range r.queryArgs {
+		p, err := g.parameter(doc, a, "query")
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, p)
+	}
+	op.add("parameters", params)
+
+	if len(r.bodyArgs) > 0 {
+		name := schemaName(doc, r.service.Name+thriftidl.GoName(f.Name)+"Request")
+		schema, err := g.structSchema(doc, "", r.bodyArgs)
+		if err != nil {
+			return nil, err
+		}
+		addSchema(name, schema)
+		op.add("requestBody", object{
+			{"content", object{{"application/json", object{{"schema", schemaRef(name)}}}}},
+			{"required", true},
+		})
+	}
+
+	ok := object{{"description", "OK"}}
+	if f.Returns != nil {
+		schema, err := g.schema(doc, f.Returns, "")
+		if err != nil {
+			return nil, err
+		}
+		ok.add("content", object{{"application/json", object{{"schema", schema}}}})
+	}
+	op.add("responses", object{
+		{"200", ok},
+		{"default", object{
+			{"description", "Error"},
+			{"content", object{{"application/json", object{{"schema", schemaRef(errorSchema)}}}}},
+		}},
+	})
+	return op, nil
+}
+
+// parameter 路径与 query 参数由 form 解码，枚举只能使用数值
+func (g *generator) parameter(doc *thriftidl.Document, a *thriftidl.Field, in string) (object, error) {
+	ref, err := g.Resolve(doc, a.Type)
+	if err != nil {
+		return nil, err
+	}
+	var schema object
+	desc := a.Doc
+	if ref.Enum != nil {
+		var values []interface{}
+		var names []string
+		for _, v := range ref.Enum.Values {
+			values = append(values, v.Value)
+			names = append(names, fmt.Sprintf("%d %s", v.Value, v.Name))
+		}
+		schema = object{{"type", "integer"}, {"format", "int32"}, {"enum", values}}
+		desc = strings.TrimSpace(desc + "\n" + ref.Name + ": " + strings.Join(names, ", "))
+	} else if schema, err = g.schema(doc, a.Type, ""); err != nil {
+		return nil, err
+	}
+	if a.Default != nil {
+		schema = append(schema, member{"default", constValue(a.Default, ref)})
+	}
+	p := object{{"name", a.Name}, {"in", in}}
+	p.add("description", desc)
+	p.add("required", in == "path")
+	p.add("schema", schema)
+	return p, nil
+}
+
+// structSchema 结构体或请求体的 schema
+//
+// 没有 optional 的结构体、容器与 binary 字段为 nil 时编码为 null，标记为 nullable；optional 字段为空时省略。
+func (g *generator) structSchema(doc *thriftidl.Document, description string, fields []*thriftidl.Field) (object, error) {
+	props := object{}
+	var required []string
+	for _, f := range fields {
+		ref, err := g.Resolve(doc, f.Type)
+		if err != nil {
+			return nil, err
+		}
+		schema, err := g.schema(doc, f.Type, f.Doc)
+		if err != nil {
+			return nil, err
+		}
+		nullable := f.Requiredness != thriftidl.Optional &&
+			(ref.Struct != nil || ref.Type.IsContainer() || ref.Type.Name == thriftidl.TypeBinary)
+		if (nullable || f.Default != nil) && schema[0].key == "$ref" {
+			// $ref 旁边的关键字会被忽略
+			schema = object{{"allOf", []interface{}{schema}}}
+		}
+		schema.add("nullable", nullable)
+		if f.Default != nil {
+			schema = append(schema, member{"default", constValue(f.Default, ref)})
+		}
+		if f.Requiredness == thriftidl.Required {
+			required = append(required, f.Name)
+		}
+		props = append(props, member{f.Name, schema})
+	}
+	schema := object{{"type", "object"}}
+	schema.add("properties", props)
+	schema.add("required", required)
+	schema.add("description", description)
+	return schema, nil
+}
+
+// schema 类型对应的 schema，引用结构体或枚举且有说明时用 allOf 包装
+func (g *generator) schema(doc *thriftidl.Document, t *thriftidl.Type, description string) (object, error) {
+	ref, err := g.Resolve(doc, t)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Enum != nil || ref.Struct != nil {
+		target := schemaRef(schemaName(ref.Doc, ref.Name))
+		if description == "" {
+			return target, nil
+		}
+		return object{{"allOf", []interface{}{target}}, {"description", description}}, nil
+	}
+	var schema object
+	switch ref.Type.Name {
+	case thriftidl.TypeBool:
+		schema = object{{"type", "boolean"}}
+	case thriftidl.TypeByte, thriftidl.TypeI8, thriftidl.TypeI16, thriftidl.TypeI32:
+		schema = object{{"type", "integer"}, {"format", "int32"}}
+	case thriftidl.TypeI64:
+		schema = object{{"type", "integer"}, {"format", "int64"}}
+	case thriftidl.TypeDouble:
+		schema = object{{"type", "number"}, {"format", "double"}}
+	case thriftidl.TypeString:
+		schema = object{{"type", "string"}}
+	case thriftidl.TypeBinary:
+		schema = object{{"type", "string"}, {"format", "byte"}}
+	case thriftidl.TypeList, thriftidl.TypeSet:
+		items, err := g.schema(doc, ref.Type.Value, "")
+		if err != nil {
+			return nil, err
+		}
+		schema = object{{"type", "array"}, {"items", items}}
+		schema.add("uniqueItems", ref.Type.Name == thriftidl.TypeSet)
+	case thriftidl.TypeMap:
+		values, err := g.schema(doc, ref.Type.Value, "")
+		if err != nil {
+			return nil, err
+		}
+		schema = object{{"type", "object"}, {"additionalProperties", values}}
+	default:
+		return nil, fmt.Errorf("不支持的类型 %s", t)
+	}
+	schema.add("description", description)
+	return schema, nil
+}
+
+// enumSchema 枚举在 JSON 中编码为名字
+func enumSchema(e *thriftidl.Enum) object {
+	var names []string
+	var docs []string
+	for _, v := range e.Values {
+		names = append(names, v.Name)
+		line := v.Name + " = " + strconv.FormatInt(v.Value, 10)
+		if v.Doc != "" {
+			line += ": " + v.Doc
+		}
+		docs = append(docs, line)
+	}
+	desc := strings.TrimSpace(e.Doc + "\n\n" + strings.Join(docs, "\n"))
+	return object{{"enum", names}, {"type", "string"}, {"description", desc}, {"format", "enum"}}
+}
+
+// constValue 把 IDL 中的默认值转换为 JSON 中的取值
+func constValue(v *thriftidl.ConstValue, ref *thriftidl.Resolved) interface{} {
+	switch v.Kind {
+	case thriftidl.ConstInt:
+		if ref.Enum != nil {
+			for _, ev := range ref.Enum.Values {
+				if ev.Value == v.Int {
+					return ev.Name
+				}
+			}
+		}
+		if ref.Type.Name == thriftidl.TypeBool {
+			return v.Int != 0
+		}
+		return v.Int
+	case thriftidl.ConstDouble:
+		return v.Double
+	case thriftidl.ConstString:
+		return v.String
+	case thriftidl.ConstIdentifier:
+		switch v.String {
+		case "true":
+			return true
+		case "false":
+			return false
+		}
+		// 枚举值写作 Enum.VALUE
+		return v.String[strings.LastIndexByte(v.String, '.')+1:]
+	case thriftidl.ConstList:
+		list := []interface{}{}
+		for _, item := range v.List {
+			list = append(list, constValue(item, &thriftidl.Resolved{Type: &thriftidl.Type{}}))
+		}
+		return list
+	case thriftidl.ConstMap:
+		m := object{}
+		for _, kv := range v.Map {
+			m = append(m, member{fmt.Sprint(constValue(kv.Key, &thriftidl.Resolved{Type: &thriftidl.Type{}})), constValue(kv.Value, &thriftidl.Resolved{Type: &thriftidl.Type{}})})
+		}
+		if len(m) == 0 {
+			return map[string]interface{}{}
+		}
+		return m
+	}
+	return nil
+}
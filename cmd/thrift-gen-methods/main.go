@@ -0,0 +1,125 @@
+// thrift-gen-methods 根据 .thrift 文件生成各服务方法的参数与结果结构体构造函数表
+//
+// 每个文件生成 {go_out}/{namespace}/{name}-methods.go，每个服务一个 {Service}Methods，键为 IDL 中的方法名。
+// 服务端在 kratos 中间件之前按此表解码参数，IDL 新增方法后重新生成即可，不需要手工登记。
+//
+//	go run ./cmd/thrift-gen-methods -go_out ./api/gen-go api/*.thrift internal/pkg/health/health.thrift
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+var goOut = flag.String("go_out", "", "生成的 Go 代码目录，每个文件写入 {go_out}/{namespace}/{name}-methods.go")
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 || *goOut == "" {
+		fmt.Fprintln(os.Stderr, "usage: thrift-gen-methods -go_out dir file.thrift...")
+		os.Exit(2)
+	}
+	if err := run(flag.Args(), *goOut); err != nil {
+		fmt.Fprintln(os.Stderr, "thrift-gen-methods:", err)
+		os.Exit(1)
+	}
+}
+
+func run(files []string, goOut string) error {
+	set, err := thriftidl.ParseFiles(files...)
+	if err != nil {
+		return err
+	}
+	for _, doc := range set.Docs {
+		if len(doc.Services) == 0 {
+			continue
+		}
+		src, err := generate(doc)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(goOut, doc.Namespace("go"), doc.BaseName()+"-methods.go")
+		if err := os.WriteFile(path, src, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var methodsTemplate = template.Must(template.New("methods").Parse(`// Code generated by thrift-gen-methods. DO NOT EDIT.
+// source: {{.Source}}
+
+package {{.Package}}
+
+import (
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+{{range .Services}}
+// {{.Name}}Methods {{.Name}} 各方法的参数与结果结构体，键为 IDL 中的方法名
+var {{.Name}}Methods = map[string]struct {
+	Args   func() thrift.TStruct
+	Result func() thrift.TStruct
+}{
+{{- range .Methods}}
+	"{{.Name}}": {
+		Args:   func() thrift.TStruct { return New{{.Type}}Args() },
+		Result: func() thrift.TStruct { return New{{.Type}}Result() },
+	},
+{{- end}}
+}
+{{end}}`))
+
+type methodsFile struct {
+	Source   string
+	Package  string
+	Services []*methodsService
+}
+
+type methodsService struct {
+	Name    string
+	Methods []*method
+}
+
+// method Type 为生成的参数、结果结构体去掉 Args、Result 后缀的名字，如 GiftServiceSendGift
+type method struct {
+	Name string
+	Type string
+}
+
+// generate 为 doc 中的每个服务生成 {Service}Methods
+func generate(doc *thriftidl.Document) ([]byte, error) {
+	if doc.Namespace("go") == "" {
+		return nil, fmt.Errorf("%s: 缺少 namespace go", doc.Filename)
+	}
+	file := &methodsFile{Source: doc.Filename, Package: doc.Namespace("go")}
+	for _, svc := range doc.Services {
+		if svc.Extends != "" {
+			return nil, fmt.Errorf("%s: 暂不支持 extends", svc.Name)
+		}
+		s := &methodsService{Name: svc.Name}
+		for _, f := range svc.Functions {
+			// oneway 方法没有结果结构体，服务端也不会写回响应
+			if f.Oneway {
+				return nil, fmt.Errorf("%s.%s: 暂不支持 oneway", svc.Name, f.Name)
+			}
+			s.Methods = append(s.Methods, &method{Name: f.Name, Type: svc.Name + thriftidl.GoName(f.Name)})
+		}
+		file.Services = append(file.Services, s)
+	}
+	var buf bytes.Buffer
+	if err := methodsTemplate.Execute(&buf, file); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化 %s 生成的代码失败: %w", doc.Filename, err)
+	}
+	return src, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+// idlFiles 服务端多路处理器中注册的全部服务的 IDL
+var idlFiles = []string{
+	"../../api/gift_service.thrift",
+	"../../api/user_service.thrift",
+	"../../internal/pkg/health/health.thrift",
+	"../../internal/pkg/reflection/reflection.thrift",
+}
+
+// TestGeneratedUpToDate 测试提交的方法表与 IDL 保持一致
+func TestGeneratedUpToDate(t *testing.T) {
+	out := t.TempDir()
+	for _, f := range idlFiles {
+		doc, err := thriftidl.ParseFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(out, doc.Namespace("go")), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := run(idlFiles, out); err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+
+	generated, err := filepath.Glob(filepath.Join(out, "*", "*-methods.go"))
+	if err != nil || len(generated) != len(idlFiles) {
+		t.Fatalf("期望生成 %d 个文件, 实际 %v: %v", len(idlFiles), generated, err)
+	}
+	for _, g := range generated {
+		rel, _ := filepath.Rel(out, g)
+		want := filepath.Join("../../api/gen-go", rel)
+		a, err := os.ReadFile(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s 与 IDL 不一致，请执行 make methods 重新生成", want)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, src := range []string{
+		`service S { void f() }`,
+		`namespace go demo
+service S extends Base { void f() }`,
+		`namespace go demo
+service S { oneway void f() }`,
+	} {
+		doc, err := thriftidl.Parse("demo.thrift", []byte(src))
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		if _, err := generate(doc); err == nil {
+			t.Errorf("期望生成失败: %s", src)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	doc, err := thriftidl.Parse("demo.thrift", []byte(`namespace go demo
+service Demo { i64 get_user(1: i64 id) }`))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	src, err := generate(doc)
+	if err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	for _, want := range []string{
+		"package demo",
+		"var DemoMethods = map[string]struct {",
+		`"get_user": {`,
+		"return NewDemoGetUserArgs()",
+		"return NewDemoGetUserResult()",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("生成的代码缺少 %q:\n%s", want, src)
+		}
+	}
+}
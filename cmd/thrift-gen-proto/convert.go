@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path/filepath"
+	"sort"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+// goImportPath 生成的 protobuf Go 包路径
+func (g *generator) goImportPath(doc *thriftidl.Document) string {
+	return g.goPackage + "/" + doc.Namespace("go") + "/v1"
+}
+
+// goFile 生成一个转换文件时收集的 import，键为包路径，值为包名
+type goFile struct {
+	g       *generator
+	doc     *thriftidl.Document
+	imports map[string]string
+}
+
+// thriftQual 引用 thrift 生成的类型或函数时的包名前缀
+func (f *goFile) thriftQual(doc *thriftidl.Document) string {
+	ns := doc.Namespace("go")
+	f.imports[f.g.thriftPackage+"/"+ns] = ns
+	return ns + "."
+}
+
+// pbQual 引用 protobuf 类型或转换函数时的包名前缀，同一个文件中定义的不需要前缀
+func (f *goFile) pbQual(doc *thriftidl.Document) string {
+	if doc == f.doc {
+		return ""
+	}
+	alias := doc.Namespace("go") + "v1"
+	f.imports[f.g.goImportPath(doc)] = alias
+	return alias + "."
+}
+
+func (g *generator) generateConvert(doc *thriftidl.Document) ([]byte, error) {
+	f := &goFile{g: g, doc: doc, imports: map[string]string{}}
+	var body bytes.Buffer
+	for _, e := range doc.Enums {
+		thriftType := f.thriftQual(doc) + e.Name
+		fmt.Fprintf(&body, "// %sFromThrift 把 thrift 枚举 %s 转换为 protobuf 枚举\n", e.Name, thriftType)
+		fmt.Fprintf(&body, "func %sFromThrift(v %s) %s {\n\treturn %s(v)\n}\n\n", e.Name, thriftType, e.Name, e.Name)
+		fmt.Fprintf(&body, "// %sToThrift 把 protobuf 枚举转换为 thrift 枚举 %s\n", e.Name, thriftType)
+		fmt.Fprintf(&body, "func %sToThrift(v %s) %s {\n\treturn %s(v)\n}\n\n", e.Name, e.Name, thriftType, thriftType)
+	}
+	for _, s := range doc.Structs {
+		for _, toThrift := range []bool{false, true} {
+			if err := f.writeStruct(&body, s, toThrift); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by thrift-gen-proto. DO NOT EDIT.\n")
+	fmt.Fprintf(&buf, "// source: %s\n\n", doc.Filename)
+	buf.WriteString("package v1\n\n")
+	if len(f.imports) > 0 {
+		paths := make([]string, 0, len(f.imports))
+		for path := range f.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		buf.WriteString("import (\n")
+		for _, path := range paths {
+			buf.WriteString("\t")
+			if alias := f.imports[path]; alias != filepath.Base(path) {
+				buf.WriteString(alias + " ")
+			}
+			fmt.Fprintf(&buf, "%q\n", path)
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body.Bytes())
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("%s: 格式化生成的转换代码失败: %w", doc.Filename, err)
+	}
+	return src, nil
+}
+
+func (f *goFile) writeStruct(w *bytes.Buffer, s *thriftidl.Struct, toThrift bool) error {
+	thriftType := f.thriftQual(f.doc) + s.Name
+	if toThrift {
+		fmt.Fprintf(w, "// %sToThrift 把 protobuf 消息转换为 thrift 结构体 %s，未设置的字段使用 IDL 中的默认值\n", s.Name, thriftType)
+		fmt.Fprintf(w, "func %sToThrift(in *%s) *%s {\n", s.Name, s.Name, thriftType)
+		fmt.Fprintf(w, "\tif in == nil {\n\t\treturn nil\n\t}\n\tout := %sNew%s()\n", f.thriftQual(f.doc), s.Name)
+	} else {
+		fmt.Fprintf(w, "// %sFromThrift 把 thrift 结构体 %s 转换为 protobuf 消息\n", s.Name, thriftType)
+		fmt.Fprintf(w, "func %sFromThrift(in *%s) *%s {\n", s.Name, thriftType, s.Name)
+		fmt.Fprintf(w, "\tif in == nil {\n\t\treturn nil\n\t}\n\tout := &%s{}\n", s.Name)
+	}
+	for _, field := range s.Fields {
+		src, dst := thriftidl.GoName(field.Name), pbGoName(snakeCase(field.Name))
+		if toThrift {
+			src, dst = dst, src
+		}
+		if err := f.writeField(w, field, "in."+src, "out."+dst, toThrift); err != nil {
+			return fmt.Errorf("%s.%s: %w", s.Name, field.Name, err)
+		}
+	}
+	w.WriteString("\treturn out\n}\n\n")
+	return nil
+}
+
+func (f *goFile) writeField(w *bytes.Buffer, field *thriftidl.Field, src, dst string, toThrift bool) error {
+	ref, err := f.g.Resolve(f.doc, field.Type)
+	if err != nil {
+		return err
+	}
+	optional, err := f.g.hasPresence(f.doc, field)
+	if err != nil {
+		return err
+	}
+	switch {
+	case optional:
+		// 两边都是指针，复制一份避免共享
+		conv, err := f.valueConv(ref, toThrift)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\tif %s != nil {\n\t\tv := %s\n\t\t%s = &v\n\t}\n", src, apply(conv, "*"+src), dst)
+	case ref.Type.Name == thriftidl.TypeList || ref.Type.Name == thriftidl.TypeSet:
+		elem, err := f.g.Resolve(f.doc, ref.Type.Value)
+		if err != nil {
+			return err
+		}
+		conv, err := f.valueConv(elem, toThrift)
+		if err != nil {
+			return err
+		}
+		if conv == nil {
+			fmt.Fprintf(w, "\t%s = %s\n", dst, src)
+			return nil
+		}
+		typ, err := f.goType(elem, toThrift)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\tif %s != nil {\n\t\t%s = make([]%s, 0, len(%s))\n", src, dst, typ, src)
+		fmt.Fprintf(w, "\t\tfor _, v := range %s {\n\t\t\t%s = append(%s, %s)\n\t\t}\n\t}\n", src, dst, dst, apply(conv, "v"))
+	case ref.Type.Name == thriftidl.TypeMap:
+		key, err := f.g.Resolve(f.doc, ref.Type.Key)
+		if err != nil {
+			return err
+		}
+		elem, err := f.g.Resolve(f.doc, ref.Type.Value)
+		if err != nil {
+			return err
+		}
+		keyConv, err := f.valueConv(key, toThrift)
+		if err != nil {
+			return err
+		}
+		conv, err := f.valueConv(elem, toThrift)
+		if err != nil {
+			return err
+		}
+		if keyConv == nil && conv == nil {
+			fmt.Fprintf(w, "\t%s = %s\n", dst, src)
+			return nil
+		}
+		keyType, err := f.goType(key, toThrift)
+		if err != nil {
+			return err
+		}
+		typ, err := f.goType(elem, toThrift)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\tif %s != nil {\n\t\t%s = make(map[%s]%s, len(%s))\n", src, dst, keyType, typ, src)
+		fmt.Fprintf(w, "\t\tfor k, v := range %s {\n\t\t\t%s[%s] = %s\n\t\t}\n\t}\n", src, dst, apply(keyConv, "k"), apply(conv, "v"))
+	default:
+		conv, err := f.valueConv(ref, toThrift)
+		if err != nil {
+			return err
+		}
+		if toThrift && ref.Enum != nil && unspecified(ref.Enum) != "" && field.Default != nil {
+			// 补充的 0 值在 thrift 中不存在，保留 IDL 中的默认值
+			fmt.Fprintf(w, "\tif %s != %s%s_%s {\n\t\t%s = %s\n\t}\n",
+				src, f.pbQual(ref.Doc), ref.Name, unspecified(ref.Enum), dst, apply(conv, src))
+			return nil
+		}
+		fmt.Fprintf(w, "\t%s = %s\n", dst, apply(conv, src))
+	}
+	return nil
+}
+
+// valueConv 标量、枚举或结构体在两种类型之间的转换，返回 nil 表示可以直接赋值
+func (f *goFile) valueConv(ref *thriftidl.Resolved, toThrift bool) (func(string) string, error) {
+	if ref.Type.IsContainer() {
+		return nil, fmt.Errorf("不支持嵌套的容器 %s", ref.Type)
+	}
+	if ref.Enum != nil || ref.Struct != nil {
+		fn := f.pbQual(ref.Doc) + ref.Name + "FromThrift"
+		if toThrift {
+			fn = f.pbQual(ref.Doc) + ref.Name + "ToThrift"
+		}
+		return func(v string) string { return fn + "(" + v + ")" }, nil
+	}
+	switch ref.Type.Name {
+	case thriftidl.TypeByte, thriftidl.TypeI8, thriftidl.TypeI16:
+		typ, err := f.goType(ref, toThrift)
+		if err != nil {
+			return nil, err
+		}
+		return func(v string) string { return typ + "(" + v + ")" }, nil
+	}
+	return nil, nil
+}
+
+// goType 值在目标一侧的 Go 类型
+func (f *goFile) goType(ref *thriftidl.Resolved, toThrift bool) (string, error) {
+	switch {
+	case ref.Enum != nil && toThrift:
+		return f.thriftQual(ref.Doc) + ref.Name, nil
+	case ref.Struct != nil && toThrift:
+		return "*" + f.thriftQual(ref.Doc) + ref.Name, nil
+	case ref.Enum != nil:
+		return f.pbQual(ref.Doc) + ref.Name, nil
+	case ref.Struct != nil:
+		return "*" + f.pbQual(ref.Doc) + ref.Name, nil
+	}
+	switch ref.Type.Name {
+	case thriftidl.TypeBool:
+		return "bool", nil
+	case thriftidl.TypeByte, thriftidl.TypeI8:
+		if toThrift {
+			return "int8", nil
+		}
+		return "int32", nil
+	case thriftidl.TypeI16:
+		if toThrift {
+			return "int16", nil
+		}
+		return "int32", nil
+	case thriftidl.TypeI32:
+		return "int32", nil
+	case thriftidl.TypeI64:
+		return "int64", nil
+	case thriftidl.TypeDouble:
+		return "float64", nil
+	case thriftidl.TypeString:
+		return "string", nil
+	case thriftidl.TypeBinary:
+		return "[]byte", nil
+	}
+	return "", fmt.Errorf("不支持的类型 %s", ref.Type)
+}
+
+func apply(conv func(string) string, v string) string {
+	if conv == nil {
+		return v
+	}
+	return conv(v)
+}
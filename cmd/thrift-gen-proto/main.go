@@ -0,0 +1,95 @@
+// thrift-gen-proto 根据 .thrift 文件生成等价的 .proto 文件，以及 thrift 与 protobuf 类型之间的转换函数
+//
+// 每个文件生成 {out}/{namespace}/v1/{name}.proto 与 {name}_thrift.go，protobuf 包名为 {namespace}.v1。
+// 结构体映射为同名消息，字段编号与 thrift 字段 ID 一致，字段名转换为 snake_case；
+// 枚举缺少 0 值时补充 {ENUM}_UNSPECIFIED，枚举值统一加上 {ENUM}_ 前缀；
+// 方法的参数合并为 {Method}Request，返回结构体时直接返回该消息，void 返回 google.protobuf.Empty，
+// 其他类型包装为 {Method}Response 的 result 字段。
+//
+//	go run ./cmd/thrift-gen-proto -out ./api api/*.thrift
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+var (
+	out           = flag.String("out", "", "输出目录，一般与 protoc 的 --proto_path 一致")
+	goPackage     = flag.String("go_package", "aboveThriftRPC/api", "生成的 protobuf Go 包路径前缀")
+	thriftPackage = flag.String("thrift_package", "aboveThriftRPC/api/gen-go", "thrift 生成的 Go 包路径前缀")
+)
+
+func main() {
+	flag.Parse()
+	if flag.NArg() == 0 || *out == "" {
+		fmt.Fprintln(os.Stderr, "usage: thrift-gen-proto -out dir file.thrift...")
+		os.Exit(2)
+	}
+	if err := run(flag.Args(), *out, *goPackage, *thriftPackage); err != nil {
+		fmt.Fprintln(os.Stderr, "thrift-gen-proto:", err)
+		os.Exit(1)
+	}
+}
+
+func run(files []string, out, goPackage, thriftPackage string) error {
+	g, err := newGenerator(files, goPackage, thriftPackage)
+	if err != nil {
+		return err
+	}
+	for _, doc := range g.Docs {
+		proto, err := g.generateProto(doc)
+		if err != nil {
+			return err
+		}
+		src, err := g.generateConvert(doc)
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(out, filepath.Dir(g.protoPath(doc)))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(out, g.protoPath(doc)), proto, 0o644); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, doc.BaseName()+"_thrift.go"), src, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generator 保存全部解析结果与包路径前缀
+type generator struct {
+	*thriftidl.Set
+	goPackage     string
+	thriftPackage string
+}
+
+func newGenerator(files []string, goPackage, thriftPackage string) (*generator, error) {
+	set, err := thriftidl.ParseFiles(files...)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range set.Docs {
+		if doc.Namespace("go") == "" {
+			return nil, fmt.Errorf("%s: 缺少 namespace go", doc.Filename)
+		}
+	}
+	return &generator{Set: set, goPackage: goPackage, thriftPackage: thriftPackage}, nil
+}
+
+// protoPath 生成的 .proto 文件相对输出目录的路径，也是其他文件 import 的路径
+func (g *generator) protoPath(doc *thriftidl.Document) string {
+	return doc.Namespace("go") + "/v1/" + doc.BaseName() + ".proto"
+}
+
+// protoPackage protobuf 包名
+func protoPackage(doc *thriftidl.Document) string {
+	return doc.Namespace("go") + ".v1"
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+// TestGeneratedUpToDate 测试提交的 .proto 与转换代码与 IDL 保持一致
+func TestGeneratedUpToDate(t *testing.T) {
+	files, err := filepath.Glob("../../api/*.thrift")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("找不到 thrift 文件: %v", err)
+	}
+	out := t.TempDir()
+	if err := run(files, out, "aboveThriftRPC/api", "aboveThriftRPC/api/gen-go"); err != nil {
+		t.Fatalf("生成失败: %v", err)
+	}
+	generated, err := filepath.Glob(filepath.Join(out, "*", "v1", "*"))
+	if err != nil || len(generated) == 0 {
+		t.Fatalf("没有生成文件: %v", err)
+	}
+	for _, g := range generated {
+		rel, _ := filepath.Rel(out, g)
+		a, err := os.ReadFile(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := os.ReadFile(filepath.Join("../../api", rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("api/%s 与 IDL 不一致，请执行 make proto 重新生成", rel)
+		}
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	for _, src := range []string{
+		`namespace go demo
+struct A { 1: list<list<i64>> ids }`,
+		`namespace go demo
+struct A { 1: map<double, string> m }`,
+		`namespace go demo
+struct A { i64 id }`,
+		`namespace go demo
+service S extends Base { void f() }`,
+		`namespace go demo
+struct FRequest { 1: i64 id }
+service S { void f() }`,
+	} {
+		doc, err := thriftidl.Parse("demo.thrift", []byte(src))
+		if err != nil {
+			t.Fatalf("解析失败: %v", err)
+		}
+		g := &generator{Set: thriftidl.NewSet(doc)}
+		if _, err := g.generateProto(doc); err == nil {
+			t.Errorf("期望生成失败: %s", strings.SplitN(src, "\n", 2)[1])
+		}
+	}
+}
+
+func TestEnumValues(t *testing.T) {
+	values, err := enumValues(&thriftidl.Enum{Name: "UserRole", Values: []*thriftidl.EnumValue{
+		{Name: "ADMIN", Value: 1},
+		{Name: "USER_ROLE_GUEST", Value: 3},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range values {
+		got = append(got, v.name)
+	}
+	if want := "USER_ROLE_UNSPECIFIED USER_ROLE_ADMIN USER_ROLE_GUEST"; strings.Join(got, " ") != want {
+		t.Fatalf("枚举值为 %v, 期望 %s", got, want)
+	}
+}
+
+func TestNames(t *testing.T) {
+	for name, want := range map[string]string{
+		"id":         "id",
+		"zipCode":    "zip_code",
+		"isActive":   "is_active",
+		"HTTPCode":   "http_code",
+		"created_at": "created_at",
+	} {
+		if got := snakeCase(name); got != want {
+			t.Errorf("snakeCase(%q) = %q, 期望 %q", name, got, want)
+		}
+	}
+	if got := pbGoName("zip_code"); got != "ZipCode" {
+		t.Errorf("pbGoName(zip_code) = %q", got)
+	}
+	if got := rpcName("echoData"); got != "EchoData" {
+		t.Errorf("rpcName(echoData) = %q", got)
+	}
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+)
+
+// protoFile 生成一个 .proto 文件时收集的 import
+type protoFile struct {
+	imports map[string]bool
+}
+
+func (g *generator) generateProto(doc *thriftidl.Document) ([]byte, error) {
+	f := &protoFile{imports: map[string]bool{}}
+	var body bytes.Buffer
+	names := map[string]bool{}
+	for _, e := range doc.Enums {
+		names[e.Name] = true
+		if err := g.writeEnum(&body, e); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range doc.Structs {
+		names[s.Name] = true
+		if err := g.writeMessage(&body, f, doc, s.Name, s.Doc, s.Fields); err != nil {
+			return nil, err
+		}
+	}
+	for _, svc := range doc.Services {
+		if svc.Extends != "" {
+			return nil, fmt.Errorf("%s: 不支持继承的服务 %s", doc.Filename, svc.Name)
+		}
+		var rpcs, messages bytes.Buffer
+		writeComment(&rpcs, "", svc.Doc)
+		fmt.Fprintf(&rpcs, "service %s {\n", svc.Name)
+		for i, fn := range svc.Functions {
+			method := rpcName(fn.Name)
+			request := method + "Request"
+			if names[request] {
+				return nil, fmt.Errorf("%s: %s 与已有的类型重名", doc.Filename, request)
+			}
+			names[request] = true
+			if err := g.writeMessage(&messages, f, doc, request, "", fn.Args); err != nil {
+				return nil, err
+			}
+			response, err := g.responseType(&messages, f, doc, fn, names)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				rpcs.WriteString("\n")
+			}
+			writeComment(&rpcs, "  ", fn.Doc)
+			fmt.Fprintf(&rpcs, "  rpc %s(%s) returns (%s);\n", method, request, response)
+		}
+		rpcs.WriteString("}\n\n")
+		body.Write(rpcs.Bytes())
+		body.Write(messages.Bytes())
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by thrift-gen-proto from %s. DO NOT EDIT.\n\n", doc.Filename)
+	buf.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&buf, "package %s;\n\n", protoPackage(doc))
+	if len(f.imports) > 0 {
+		imports := make([]string, 0, len(f.imports))
+		for imp := range f.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		for _, imp := range imports {
+			fmt.Fprintf(&buf, "import %q;\n", imp)
+		}
+		buf.WriteString("\n")
+	}
+	fmt.Fprintf(&buf, "option go_package = %q;\n\n", g.goImportPath(doc)+";v1")
+	buf.Write(bytes.TrimRight(body.Bytes(), "\n"))
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// responseType 返回方法的响应类型，需要包装时写入 {Method}Response 消息
+func (g *generator) responseType(w *bytes.Buffer, f *protoFile, doc *thriftidl.Document, fn *thriftidl.Function, names map[string]bool) (string, error) {
+	if fn.Returns == nil {
+		f.imports["google/protobuf/empty.proto"] = true
+		return "google.protobuf.Empty", nil
+	}
+	ref, err := g.Resolve(doc, fn.Returns)
+	if err != nil {
+		return "", err
+	}
+	if ref.Struct != nil {
+		return g.protoType(f, doc, fn.Returns)
+	}
+	response := rpcName(fn.Name) + "Response"
+	if names[response] {
+		return "", fmt.Errorf("%s: %s 与已有的类型重名", doc.Filename, response)
+	}
+	names[response] = true
+	result := &thriftidl.Field{ID: 1, Name: "result", Type: fn.Returns}
+	if err := g.writeMessage(w, f, doc, response, "", []*thriftidl.Field{result}); err != nil {
+		return "", err
+	}
+	return response, nil
+}
+
+func (g *generator) writeEnum(w *bytes.Buffer, e *thriftidl.Enum) error {
+	writeComment(w, "", e.Doc)
+	fmt.Fprintf(w, "enum %s {\n", e.Name)
+	values, err := enumValues(e)
+	if err != nil {
+		return err
+	}
+	for _, v := range values {
+		writeComment(w, "  ", v.doc)
+		fmt.Fprintf(w, "  %s = %d;\n", v.name, v.value)
+	}
+	w.WriteString("}\n\n")
+	return nil
+}
+
+func (g *generator) writeMessage(w *bytes.Buffer, f *protoFile, doc *thriftidl.Document, name, comment string, fields []*thriftidl.Field) error {
+	writeComment(w, "", comment)
+	if len(fields) == 0 {
+		fmt.Fprintf(w, "message %s {}\n\n", name)
+		return nil
+	}
+	fmt.Fprintf(w, "message %s {\n", name)
+	for _, field := range fields {
+		if field.ID <= 0 {
+			return fmt.Errorf("%s: %s.%s 缺少字段 ID", doc.Filename, name, field.Name)
+		}
+		typ, err := g.protoType(f, doc, field.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", name, field.Name, err)
+		}
+		optional, err := g.hasPresence(doc, field)
+		if err != nil {
+			return err
+		}
+		if optional {
+			typ = "optional " + typ
+		}
+		writeComment(w, "  ", field.Doc)
+		fmt.Fprintf(w, "  %s %s = %d;\n", typ, snakeCase(field.Name), field.ID)
+	}
+	w.WriteString("}\n\n")
+	return nil
+}
+
+// hasPresence thrift optional 的标量字段生成 proto3 optional，两边的 Go 字段都是指针
+func (g *generator) hasPresence(doc *thriftidl.Document, field *thriftidl.Field) (bool, error) {
+	if field.Requiredness != thriftidl.Optional {
+		return false, nil
+	}
+	ref, err := g.Resolve(doc, field.Type)
+	if err != nil {
+		return false, err
+	}
+	return ref.IsScalar(), nil
+}
+
+// protoType thrift 类型对应的 protobuf 类型，引用其他文件的类型时记录 import
+func (g *generator) protoType(f *protoFile, doc *thriftidl.Document, t *thriftidl.Type) (string, error) {
+	ref, err := g.Resolve(doc, t)
+	if err != nil {
+		return "", err
+	}
+	if ref.Enum != nil || ref.Struct != nil {
+		if ref.Doc == doc {
+			return ref.Name, nil
+		}
+		f.imports[g.protoPath(ref.Doc)] = true
+		return protoPackage(ref.Doc) + "." + ref.Name, nil
+	}
+	switch ref.Type.Name {
+	case thriftidl.TypeBool:
+		return "bool", nil
+	case thriftidl.TypeByte, thriftidl.TypeI8, thriftidl.TypeI16, thriftidl.TypeI32:
+		return "int32", nil
+	case thriftidl.TypeI64:
+		return "int64", nil
+	case thriftidl.TypeDouble:
+		return "double", nil
+	case thriftidl.TypeString:
+		return "string", nil
+	case thriftidl.TypeBinary:
+		return "bytes", nil
+	case thriftidl.TypeList, thriftidl.TypeSet:
+		elem, err := g.elemType(f, doc, ref.Type.Value)
+		if err != nil {
+			return "", err
+		}
+		return "repeated " + elem, nil
+	case thriftidl.TypeMap:
+		key, err := g.Resolve(doc, ref.Type.Key)
+		if err != nil {
+			return "", err
+		}
+		if !validMapKey(key) {
+			return "", fmt.Errorf("protobuf 不支持 %s 作为 map 的键", ref.Type.Key)
+		}
+		k, err := g.protoType(f, doc, ref.Type.Key)
+		if err != nil {
+			return "", err
+		}
+		v, err := g.elemType(f, doc, ref.Type.Value)
+		if err != nil {
+			return "", err
+		}
+		return "map<" + k + ", " + v + ">", nil
+	}
+	return "", fmt.Errorf("不支持的类型 %s", t)
+}
+
+// elemType 容器元素的类型，protobuf 不支持容器嵌套
+func (g *generator) elemType(f *protoFile, doc *thriftidl.Document, t *thriftidl.Type) (string, error) {
+	ref, err := g.Resolve(doc, t)
+	if err != nil {
+		return "", err
+	}
+	if ref.Type.IsContainer() {
+		return "", fmt.Errorf("protobuf 不支持嵌套的容器 %s", t)
+	}
+	return g.protoType(f, doc, t)
+}
+
+// validMapKey map 的键只能是整数、bool 或 string
+func validMapKey(ref *thriftidl.Resolved) bool {
+	if ref.Enum != nil || ref.Struct != nil {
+		return false
+	}
+	switch ref.Type.Name {
+	case thriftidl.TypeDouble, thriftidl.TypeBinary, thriftidl.TypeList, thriftidl.TypeSet, thriftidl.TypeMap:
+		return false
+	}
+	return true
+}
+
+// enumValue 生成的 protobuf 枚举值
+type enumValue struct {
+	name  string
+	value int64
+	doc   string
+}
+
+// enumValues 枚举值加上 {ENUM}_ 前缀，缺少 0 值时在最前面补充 {ENUM}_UNSPECIFIED
+func enumValues(e *thriftidl.Enum) ([]enumValue, error) {
+	prefix := upperSnake(e.Name) + "_"
+	var values []enumValue
+	hasZero := false
+	for _, v := range e.Values {
+		if v.Value < math.MinInt32 || v.Value > math.MaxInt32 {
+			return nil, fmt.Errorf("%s.%s 超出 protobuf 枚举的取值范围", e.Name, v.Name)
+		}
+		name := v.Name
+		if !strings.HasPrefix(name, prefix) {
+			name = prefix + name
+		}
+		hasZero = hasZero || v.Value == 0
+		values = append(values, enumValue{name: name, value: v.Value, doc: v.Doc})
+	}
+	if !hasZero {
+		values = append([]enumValue{{name: unspecified(e), doc: "thrift 中没有对应的值"}}, values...)
+	}
+	return values, nil
+}
+
+// unspecified 补充的 0 值名字，枚举自身有 0 值时返回空串
+func unspecified(e *thriftidl.Enum) string {
+	for _, v := range e.Values {
+		if v.Value == 0 {
+			return ""
+		}
+	}
+	return upperSnake(e.Name) + "_UNSPECIFIED"
+}
+
+func writeComment(w *bytes.Buffer, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(w, "%s// %s\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// snakeCase 把 camelCase 转换为 snake_case：zipCode → zip_code，HTTPCode → http_code
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && runes[i-1] != '_' &&
+			(!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+func upperSnake(name string) string {
+	return strings.ToUpper(snakeCase(name))
+}
+
+// rpcName 方法名首字母大写：echoData → EchoData
+func rpcName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// pbGoName protoc-gen-go 为 snake_case 字段生成的 Go 名字：gift_id → GiftId
+func pbGoName(name string) string {
+	parts := strings.Split(name, "_")
+	for i, p := range parts {
+		if p != "" {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
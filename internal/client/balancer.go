@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/registry"
+	"github.com/go-kratos/kratos/v2/selector"
+	"github.com/go-kratos/kratos/v2/selector/node/direct"
+	"github.com/go-kratos/kratos/v2/selector/p2c"
+	"github.com/go-kratos/kratos/v2/selector/random"
+	"github.com/go-kratos/kratos/v2/selector/wrr"
+)
+
+// 负载均衡算法，见 NewSelectorBuilder
+const (
+	BalancerRoundRobin = "round_robin"
+	BalancerWeighted   = wrr.Name
+	BalancerRandom     = random.Name
+	BalancerP2C        = p2c.Name
+)
+
+// defaultNodeWeight 没有设置权重的节点的权重，与 kratos 的 direct 节点一致
+const defaultNodeWeight = 100
+
+// NewSelectorBuilder 按名称创建 kratos selector：
+//
+//   - round_robin 依次选择节点，忽略权重
+//   - wrr 平滑加权轮询，即 kratos 的 wrr
+//   - random 随机选择节点，即 kratos 的 random
+//   - p2c 随机取两个节点，选择 权重/(等待响应的请求数+1) 较大的节点
+func NewSelectorBuilder(balancer string) (selector.Builder, error) {
+	switch balancer {
+	case BalancerRoundRobin:
+		return &selector.DefaultBuilder{Node: &direct.Builder{}, Balancer: &roundRobinBuilder{}}, nil
+	case BalancerWeighted:
+		return wrr.NewBuilder(), nil
+	case BalancerRandom:
+		return random.NewBuilder(), nil
+	case BalancerP2C:
+		return &selector.DefaultBuilder{Node: &inflightNodeBuilder{}, Balancer: &p2c.Builder{}}, nil
+	default:
+		return nil, fmt.Errorf("unknown balancer: %s", balancer)
+	}
+}
+
+// WithSelector 设置 BalancedPool 的负载均衡，默认使用 kratos 的全局 selector，没有设置时为 wrr
+func WithSelector(builder selector.Builder) Option {
+	return func(c *ThriftClient) {
+		c.selector = builder
+	}
+}
+
+// WithNodeFilter 追加 BalancedPool 选择节点前的过滤器，按添加顺序执行，与 WithMiddleware 一样可以多次使用，
+// 过滤器与 kratos http、grpc 客户端的 WithNodeFilter 相同
+func WithNodeFilter(filters ...selector.NodeFilter) Option {
+	return func(c *ThriftClient) {
+		c.nodeFilters = append(c.nodeFilters, filters...)
+	}
+}
+
+// NewNode 创建服务端节点，weight 大于 0 时为节点权重，与注册中心实例 metadata 中的 weight 相同
+func NewNode(addr string, weight int64) selector.Node {
+	ins := &registry.ServiceInstance{Metadata: map[string]string{}}
+	if weight > 0 {
+		ins.Metadata["weight"] = strconv.FormatInt(weight, 10)
+	}
+	return selector.NewNode(string(thriftx.KindThrift), addr, ins)
+}
+
+// roundRobin 依次选择节点的 balancer
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+// Pick 选择下一个节点
+func (b *roundRobin) Pick(_ context.Context, nodes []selector.WeightedNode) (selector.WeightedNode, selector.DoneFunc, error) {
+	if len(nodes) == 0 {
+		return nil, nil, selector.ErrNoAvailable
+	}
+	node := nodes[(b.next.Add(1)-1)%uint64(len(nodes))]
+	return node, node.Pick(), nil
+}
+
+type roundRobinBuilder struct{}
+
+func (*roundRobinBuilder) Build() selector.Balancer {
+	return &roundRobin{}
+}
+
+// inflightNode 记录等待响应的请求数，权重为 初始权重/(请求数+1)
+type inflightNode struct {
+	selector.Node
+	inflight atomic.Int64
+	lastPick atomic.Int64
+}
+
+type inflightNodeBuilder struct{}
+
+func (*inflightNodeBuilder) Build(n selector.Node) selector.WeightedNode {
+	return &inflightNode{Node: n}
+}
+
+// Pick 选中节点，调用结束时 done
+func (n *inflightNode) Pick() selector.DoneFunc {
+	n.lastPick.Store(time.Now().UnixNano())
+	n.inflight.Add(1)
+	return func(context.Context, selector.DoneInfo) {
+		n.inflight.Add(-1)
+	}
+}
+
+// Weight 节点当前的权重
+func (n *inflightNode) Weight() float64 {
+	weight := float64(defaultNodeWeight)
+	if w := n.InitialWeight(); w != nil {
+		weight = float64(*w)
+	}
+	return weight / float64(n.inflight.Load()+1)
+}
+
+func (n *inflightNode) PickElapsed() time.Duration {
+	return time.Duration(time.Now().UnixNano() - n.lastPick.Load())
+}
+
+func (n *inflightNode) Raw() selector.Node {
+	return n.Node
+}
+
+// BalancedPool 多个服务端地址的连接池，每个地址一个 Pool，每次 Do 由 kratos selector 选择地址
+type BalancedPool[T any] struct {
+	pools    map[string]*Pool[T]
+	selector selector.Selector
+	filters  []selector.NodeFilter
+	retry    *RetryPolicy
+}
+
+// BalancedThriftConnectionPool UserService 的多地址连接池
+type BalancedThriftConnectionPool = BalancedPool[user_service.UserServiceClient]
+
+// NewBalancedPool 创建 nodes 的连接池，每个地址按 maxIdle、maxActive、idleTimeout 与 opts 创建一个 Pool，
+// 节点可以由 NewNode 创建，也可以来自注册中心。
+func NewBalancedPool[T any](nodes []selector.Node, service string, newClient func(thrift.TClient) *T, maxIdle, maxActive int, idleTimeout time.Duration, opts ...Option) *BalancedPool[T] {
+	c := NewThriftClient("", opts...)
+	builder := c.selector
+	if builder == nil {
+		builder = selector.GlobalSelector()
+	}
+	if builder == nil {
+		builder = wrr.NewBuilder()
+	}
+
+	b := &BalancedPool[T]{
+		pools:    make(map[string]*Pool[T], len(nodes)),
+		selector: builder.Build(),
+		filters:  c.nodeFilters,
+		retry:    c.retry,
+	}
+	for _, node := range nodes {
+		if _, ok := b.pools[node.Address()]; !ok {
+			b.pools[node.Address()] = NewPool(node.Address(), service, newClient, maxIdle, maxActive, idleTimeout, opts...)
+		}
+	}
+	b.selector.Apply(nodes)
+	return b
+}
+
+// NewBalancedThriftConnectionPool 创建 UserService 的多地址连接池
+func NewBalancedThriftConnectionPool(nodes []selector.Node, maxIdle, maxActive int, idleTimeout time.Duration, opts ...Option) *BalancedThriftConnectionPool {
+	return NewBalancedPool(nodes, "UserService", user_service.NewUserServiceClient, maxIdle, maxActive, idleTimeout, opts...)
+}
+
+// Do 选择一个地址，在该地址的连接池上执行 fn，连接的归还与销毁同 Pool.Do
+//
+// 开启 WithRetry 时每次重试重新选择地址。ctx 中有 selector.Peer 时写入最后一次选择的节点。
+func (b *BalancedPool[T]) Do(ctx context.Context, fn func(conn *Conn[T]) error) error {
+	return withRetry(ctx, b.retry, func() (addr string, calls callLog, borrowed bool, err error) {
+		node, done, err := b.selector.Select(ctx, selector.WithNodeFilter(b.filters...))
+		if err != nil {
+			return "", callLog{}, false, err
+		}
+		// fn panic 时同样结束本次选择，p2c 的请求数不会泄漏
+		defer func() { done(ctx, selector.DoneInfo{Err: err, BytesSent: borrowed}) }()
+		p := b.pools[node.Address()]
+		calls, borrowed, err = p.do(ctx, fn)
+		return p.addr, calls, borrowed, err
+	})
+}
+
+// Close 关闭全部地址的连接池
+func (b *BalancedPool[T]) Close(ctx context.Context) error {
+	for _, p := range b.pools {
+		p.Close(ctx)
+	}
+	return nil
+}
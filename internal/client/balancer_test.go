@@ -0,0 +1,165 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+
+	"github.com/go-kratos/kratos/v2/selector"
+)
+
+// echoPeer 通过 pool 调用 EchoData，返回选中的服务端地址
+func echoPeer(ctx context.Context, pool *BalancedThriftConnectionPool, data string) (string, error) {
+	p := &selector.Peer{}
+	ctx = selector.NewPeerContext(ctx, p)
+	err := pool.Do(ctx, func(conn *ThriftClientConn) error {
+		_, err := conn.Client.EchoData(ctx, []byte(data), &user_service.User{ID: 1})
+		return err
+	})
+	if p.Node == nil {
+		return "", err
+	}
+	return p.Node.Address(), err
+}
+
+// TestBalancedPool 测试各负载均衡算法在多个地址间的分布
+func TestBalancedPool(t *testing.T) {
+	addrs := []string{
+		startServer(t, &conf.Server_Thrift{}),
+		startServer(t, &conf.Server_Thrift{}),
+		startServer(t, &conf.Server_Thrift{}),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// count 调用 n 次，返回各地址被选中的次数
+	count := func(t *testing.T, pool *BalancedThriftConnectionPool, n int) map[string]int {
+		t.Helper()
+		counts := make(map[string]int)
+		for i := 0; i < n; i++ {
+			addr, err := echoPeer(ctx, pool, "hello")
+			if err != nil {
+				t.Fatalf("调用失败: %v", err)
+			}
+			counts[addr]++
+		}
+		return counts
+	}
+	newPool := func(t *testing.T, balancer string, nodes []selector.Node, opts ...Option) *BalancedThriftConnectionPool {
+		t.Helper()
+		builder, err := NewSelectorBuilder(balancer)
+		if err != nil {
+			t.Fatalf("创建 selector 失败: %v", err)
+		}
+		pool := NewBalancedThriftConnectionPool(nodes, 2, 2, time.Minute, append(opts, WithSelector(builder))...)
+		t.Cleanup(func() { pool.Close(context.Background()) })
+		return pool
+	}
+	nodes := []selector.Node{NewNode(addrs[0], 0), NewNode(addrs[1], 0), NewNode(addrs[2], 0)}
+
+	t.Run("round_robin", func(t *testing.T) {
+		// 忽略权重
+		pool := newPool(t, BalancerRoundRobin, []selector.Node{NewNode(addrs[0], 10), NewNode(addrs[1], 1), NewNode(addrs[2], 1)})
+		for i := 0; i < 6; i++ {
+			addr, err := echoPeer(ctx, pool, "hello")
+			if err != nil || addr != addrs[i%3] {
+				t.Fatalf("第 %d 次调用期望选择 %s, 实际 %s: %v", i, addrs[i%3], addr, err)
+			}
+		}
+	})
+
+	t.Run("weighted", func(t *testing.T) {
+		pool := newPool(t, BalancerWeighted, []selector.Node{NewNode(addrs[0], 3), NewNode(addrs[1], 1)})
+		counts := count(t, pool, 8)
+		if counts[addrs[0]] != 6 || counts[addrs[1]] != 2 {
+			t.Fatalf("期望按 3:1 选择, 实际 %v", counts)
+		}
+	})
+
+	t.Run("random", func(t *testing.T) {
+		pool := newPool(t, BalancerRandom, nodes)
+		counts := count(t, pool, 60)
+		if len(counts) != 3 {
+			t.Fatalf("期望随机选择全部地址, 实际 %v", counts)
+		}
+	})
+
+	t.Run("p2c", func(t *testing.T) {
+		pool := newPool(t, BalancerP2C, nodes[:2])
+		// 一个地址上有等待响应的请求时选择另一个地址
+		var wg sync.WaitGroup
+		wg.Add(1)
+		var busy string
+		go func() {
+			defer wg.Done()
+			busy, _ = echoPeer(ctx, pool, "slow")
+		}()
+		time.Sleep(100 * time.Millisecond)
+		counts := count(t, pool, 5)
+		wg.Wait()
+		if len(counts) != 1 || counts[busy] != 0 {
+			t.Fatalf("期望避开有请求的地址 %s, 实际 %v", busy, counts)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		exclude := func(addr string) selector.NodeFilter {
+			return func(ctx context.Context, nodes []selector.Node) []selector.Node {
+				var filtered []selector.Node
+				for _, n := range nodes {
+					if n.Address() != addr {
+						filtered = append(filtered, n)
+					}
+				}
+				return filtered
+			}
+		}
+		pool := newPool(t, BalancerRoundRobin, nodes, WithNodeFilter(exclude(addrs[0])))
+		counts := count(t, pool, 4)
+		if counts[addrs[0]] != 0 || counts[addrs[1]] != 2 || counts[addrs[2]] != 2 {
+			t.Fatalf("期望只选择过滤后的地址, 实际 %v", counts)
+		}
+
+		// 多次设置的过滤器依次执行
+		pool = newPool(t, BalancerRoundRobin, nodes, WithNodeFilter(exclude(addrs[0])), WithNodeFilter(exclude(addrs[1])))
+		counts = count(t, pool, 4)
+		if counts[addrs[2]] != 4 {
+			t.Fatalf("期望两个过滤器都生效, 实际 %v", counts)
+		}
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		// 连接失败时重试选择下一个地址
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("获取空闲端口失败: %v", err)
+		}
+		down := l.Addr().String()
+		l.Close()
+		policy := DefaultRetryPolicy()
+		policy.InitialBackoff = time.Millisecond
+		pool := newPool(t, BalancerRoundRobin, []selector.Node{NewNode(down, 0), NewNode(addrs[0], 0)}, WithRetry(policy))
+		for i := 0; i < 4; i++ {
+			if addr, err := echoPeer(ctx, pool, "hello"); err != nil || addr != addrs[0] {
+				t.Fatalf("期望重试到可用的地址, 实际 %s: %v", addr, err)
+			}
+		}
+	})
+}
+
+// TestNewSelectorBuilder 测试按名称创建 selector，未知的算法返回错误
+func TestNewSelectorBuilder(t *testing.T) {
+	for _, name := range []string{BalancerRoundRobin, BalancerWeighted, BalancerRandom, BalancerP2C} {
+		if _, err := NewSelectorBuilder(name); err != nil {
+			t.Errorf("创建 %s 失败: %v", name, err)
+		}
+	}
+	if _, err := NewSelectorBuilder("unknown"); err == nil {
+		t.Error("期望未知的算法返回错误")
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/server"
+)
+
+// echoAddr TestThriftClientEchoData 使用的服务地址
+const echoAddr = "127.0.0.1:9000"
+
+// TestMain echoAddr 上没有已运行的服务端时启动一个测试服务端
+func TestMain(m *testing.M) {
+	conn, err := net.Dial("tcp", echoAddr)
+	if err == nil {
+		conn.Close()
+		os.Exit(m.Run())
+	}
+
+	srv, err := server.NewThriftServer(&conf.Server{Thrift: &conf.Server_Thrift{Addr: echoAddr}},
+		&thrifttest.UserService{}, &thrifttest.GiftService{})
+	if err != nil {
+		panic(err)
+	}
+	stop, err := thrifttest.Serve(srv, "tcp", echoAddr)
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	stop()
+	os.Exit(code)
+}
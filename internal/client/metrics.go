@@ -0,0 +1,28 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"aboveThriftRPC/internal/pkg/metrics"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// metricsMiddleware 按服务与方法记录客户端的请求数、耗时与等待响应的请求数
+func metricsMiddleware(service string) thrift.ClientMiddleware {
+	return func(next thrift.TClient) thrift.TClient {
+		return thrift.WrappedTClient{
+			Wrapped: func(ctx context.Context, method string, args, result thrift.TStruct) (thrift.ResponseMeta, error) {
+				inFlight := metrics.ClientInFlight.WithLabelValues(service, method)
+				inFlight.Inc()
+				defer inFlight.Dec()
+				start := time.Now()
+				meta, err := next.Call(ctx, method, args, result)
+				metrics.ClientDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+				metrics.ClientRequests.WithLabelValues(service, method, metrics.Code(err)).Inc()
+				return meta, err
+			},
+		}
+	}
+}
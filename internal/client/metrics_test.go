@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestThriftClientMetrics 测试客户端请求指标与连接池状态
+func TestThriftClientMetrics(t *testing.T) {
+	addr := startServer(t, &conf.Server_Thrift{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	requests := metrics.ClientRequests.WithLabelValues("UserService", "echoData", metrics.CodeOK)
+	before := testutil.ToFloat64(requests)
+	pool := NewThriftConnectionPool(addr, 2, 2, time.Minute)
+	conn, err := pool.GetConnection(ctx)
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	if _, err := conn.Client.EchoData(ctx, []byte("metrics"), &user_service.User{ID: 1}); err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	if got := testutil.ToFloat64(requests) - before; got != 1 {
+		t.Fatalf("期望请求数增加 1, 实际 %v", got)
+	}
+
+	// 借出期间活跃连接数为 1，归还后变为空闲
+	gauge := func(name string) (float64, bool) {
+		families, err := prometheus.DefaultGatherer.Gather()
+		if err != nil {
+			t.Fatalf("采集指标失败: %v", err)
+		}
+		for _, mf := range families {
+			if mf.GetName() != name {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				if m.GetLabel()[0].GetValue() == addr {
+					return m.GetGauge().GetValue(), true
+				}
+			}
+		}
+		return 0, false
+	}
+	if active, _ := gauge("thrift_client_pool_active"); active != 1 {
+		t.Fatalf("借出期间 active 为 %v", active)
+	}
+	pool.ReleaseConnection(ctx, conn)
+	active, _ := gauge("thrift_client_pool_active")
+	idle, _ := gauge("thrift_client_pool_idle")
+	if active != 0 || idle != 1 {
+		t.Fatalf("归还后 active 为 %v, idle 为 %v", active, idle)
+	}
+	pool.Close(ctx)
+	if _, ok := gauge("thrift_client_pool_active"); ok {
+		t.Fatal("关闭连接池后期望不再采集")
+	}
+}
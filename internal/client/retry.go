@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/sirupsen/logrus"
+)
+
+// IdempotencyKeyHeader 幂等键的请求头，只有 THeader 传输层会发送给服务端
+const IdempotencyKeyHeader = thriftx.IdempotencyKeyHeader
+
+// DefaultIdempotentMethods 默认自动重试的只读方法，键为 Service.method
+var DefaultIdempotentMethods = []string{
+	"GiftService.GetGiftsBySender",
+	"GiftService.GetTop10Senders",
+	"GiftService.GetSendersInLastWeek",
+}
+
+// RetryPolicy Pool.Do 的重试策略
+type RetryPolicy struct {
+	// MaxAttempts 包含第一次调用在内的最大调用次数，小于等于 1 时不重试
+	MaxAttempts int
+	// InitialBackoff 第一次重试前的等待时间，之后每次乘以 Multiplier，不超过 MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter 等待时间随机减少的比例，取值 0~1
+	Jitter float64
+	// IdempotentMethods 传输层出错后可以重试的方法，键为 Service.method
+	IdempotentMethods []string
+}
+
+// DefaultRetryPolicy 默认的重试策略：最多调用 3 次，等待 50ms、100ms，随机减少 20%
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:       3,
+		InitialBackoff:    50 * time.Millisecond,
+		MaxBackoff:        time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		IdempotentMethods: DefaultIdempotentMethods,
+	}
+}
+
+// WithRetry 开启 Pool.Do 的重试，见 RetryPolicy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *ThriftClient) {
+		c.retry = &policy
+	}
+}
+
+// backoff 第 retry 次重试前的等待时间，retry 从 1 开始
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+type idempotentKey struct{}
+
+// WithIdempotent 标记 ctx 发起的调用是幂等的，传输层出错后可以重试
+func WithIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+// WithIdempotencyKey 为调用设置幂等键，调用重试时发送相同的幂等键，需要服务端按幂等键去重
+//
+// 幂等键只能通过 THeader 请求头发送，连接池使用 header 传输层时调用才视为幂等；
+// 其他传输层上服务端收不到幂等键，调用按非幂等处理，出错后不重试。
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	ctx = thrift.SetHeader(ctx, IdempotencyKeyHeader, key)
+	return thrift.SetWriteHeaderList(ctx, append(thrift.GetWriteHeaderList(ctx), IdempotencyKeyHeader))
+}
+
+// isIdempotent 判断 ctx 发起的调用是否标记为幂等，header 为 false 时幂等键发不到服务端，不视为幂等
+func isIdempotent(ctx context.Context, header bool) bool {
+	if v, _ := ctx.Value(idempotentKey{}).(bool); v {
+		return true
+	}
+	if !header {
+		return false
+	}
+	key, ok := thrift.GetHeader(ctx, IdempotencyKeyHeader)
+	return ok && key != ""
+}
+
+// callLog 记录一次 Do 中连接上发起的调用
+type callLog struct {
+	operation string
+	calls     int
+	// unsafe 存在没有标记为幂等的调用
+	unsafe bool
+}
+
+// callLogMiddleware 把调用记录到 log 中，idempotent 为幂等的方法，header 为连接是否使用 THeader
+func callLogMiddleware(service string, idempotent map[string]bool, header bool, log *callLog) thrift.ClientMiddleware {
+	return func(next thrift.TClient) thrift.TClient {
+		return thrift.WrappedTClient{
+			Wrapped: func(ctx context.Context, method string, args, result thrift.TStruct) (thrift.ResponseMeta, error) {
+				log.operation = thriftx.Operation(service, method)
+				log.calls++
+				if !idempotent[log.operation] && !isIdempotent(ctx, header) {
+					log.unsafe = true
+				}
+				return next.Call(ctx, method, args, result)
+			},
+		}
+	}
+}
+
+// withRetry 按 policy 执行 attempt，policy 为 nil 时只执行一次
+//
+// attempt 返回本次调用的服务端地址、发起的调用，borrowed 为 false 时没有借出连接，请求没有发出。
+func withRetry(ctx context.Context, policy *RetryPolicy, attempt func() (addr string, calls callLog, borrowed bool, err error)) error {
+	for n := 1; ; n++ {
+		addr, calls, borrowed, err := attempt()
+		if err == nil || policy == nil || n >= policy.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		if borrowed {
+			if !retryable(err, calls) {
+				return err
+			}
+		} else if !isBrokenConn(err) {
+			// 没有借出连接时只重试建立连接失败
+			return err
+		}
+
+		backoff := policy.backoff(n)
+		logrus.Warnf("thrift client call %s to %s failed, retrying in %v (attempt %d/%d): %v",
+			calls.operation, addr, backoff, n+1, policy.MaxAttempts, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable 判断失败的调用能否重试
+//
+// 服务端只在处理函数执行之前（并发上限、限流、熔断）返回 LOADSHEDDING，请求没有被处理，总是可以重试；
+// 传输层与协议错误时请求可能已经被处理，只有调用全部为幂等时重试；服务端返回的其他异常
+// （包括处理函数执行之后的 429、503，服务端写回 INTERNAL_ERROR）与业务错误不重试。
+func retryable(err error, calls callLog) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var appErr thrift.TApplicationException
+	if errors.As(err, &appErr) {
+		return appErr.TypeId() == thriftx.LOADSHEDDING
+	}
+	var transportErr thrift.TTransportException
+	var protocolErr thrift.TProtocolException
+	if !errors.As(err, &transportErr) && !errors.As(err, &protocolErr) {
+		return false
+	}
+	return calls.calls > 0 && !calls.unsafe
+}
@@ -0,0 +1,292 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/pkg/thriftx"
+	"aboveThriftRPC/internal/server"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+)
+
+// TestPoolRetry 测试幂等的调用在连接被重置后重试，非幂等的调用只有带幂等键时重试
+func TestPoolRetry(t *testing.T) {
+	upstream := startServer(t, &conf.Server_Thrift{})
+	proxy, addr := startProxy(t, upstream)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = 10 * time.Millisecond
+	gifts := NewPool(addr, "GiftService", gift_service.NewGiftServiceClient, 2, 2, time.Minute, WithRetry(policy))
+	defer gifts.Close(ctx)
+
+	// call 调用 fn 并返回执行次数
+	call := func(fn func(ctx context.Context, client *gift_service.GiftServiceClient) error) (int, error) {
+		attempts := 0
+		err := gifts.Do(ctx, func(conn *Conn[gift_service.GiftServiceClient]) error {
+			attempts++
+			return fn(ctx, conn.Client)
+		})
+		return attempts, err
+	}
+	getGifts := func(ctx context.Context, client *gift_service.GiftServiceClient) error {
+		_, err := client.GetGiftsBySender(ctx, 1)
+		return err
+	}
+	sendGift := func(ctx context.Context, client *gift_service.GiftServiceClient) error {
+		_, err := client.SendGift(ctx, 1, 2, 10, gift_service.GiftType_GIFT_TYPE_NORMAL, 1)
+		return err
+	}
+
+	proxy.resets.Store(1)
+	if attempts, err := call(getGifts); err != nil || attempts != 2 {
+		t.Fatalf("期望幂等的调用重试后成功, 实际执行 %d 次: %v", attempts, err)
+	}
+	proxy.resets.Store(1)
+	if attempts, err := call(func(ctx context.Context, client *gift_service.GiftServiceClient) error {
+		_, err := client.GetTop10Senders(ctx)
+		return err
+	}); err != nil || attempts != 2 {
+		t.Fatalf("期望幂等的调用重试后成功, 实际执行 %d 次: %v", attempts, err)
+	}
+
+	proxy.resets.Store(1)
+	var transportErr thrift.TTransportException
+	if attempts, err := call(sendGift); !stderrors.As(err, &transportErr) || attempts != 1 {
+		t.Fatalf("期望非幂等的调用不重试, 实际执行 %d 次: %v", attempts, err)
+	}
+	// buffered 传输层发送不了幂等键，带幂等键的调用同样不重试
+	proxy.resets.Store(1)
+	if attempts, err := call(func(ctx context.Context, client *gift_service.GiftServiceClient) error {
+		return sendGift(WithIdempotencyKey(ctx, "gift-1"), client)
+	}); !stderrors.As(err, &transportErr) || attempts != 1 {
+		t.Fatalf("期望非 header 传输层上带幂等键的调用不重试, 实际执行 %d 次: %v", attempts, err)
+	}
+	// 同一次执行中只要有非幂等的调用就不重试
+	proxy.resets.Store(1)
+	if attempts, _ := call(func(ctx context.Context, client *gift_service.GiftServiceClient) error {
+		if err := sendGift(ctx, client); err != nil {
+			return err
+		}
+		return getGifts(ctx, client)
+	}); attempts != 1 {
+		t.Fatalf("期望包含非幂等调用时不重试, 实际执行 %d 次", attempts)
+	}
+
+	// 超过最大次数后返回最后一次的错误
+	proxy.resets.Store(5)
+	start := time.Now()
+	if attempts, err := call(getGifts); !stderrors.As(err, &transportErr) || attempts != policy.MaxAttempts {
+		t.Fatalf("期望重试 %d 次后失败, 实际执行 %d 次: %v", policy.MaxAttempts, attempts, err)
+	}
+	if d := time.Since(start); d < 24*time.Millisecond {
+		t.Errorf("期望重试前等待退避时间, 实际耗时 %v", d)
+	}
+	proxy.resets.Store(0)
+
+	// 服务端返回的异常不重试
+	if attempts, err := call(func(ctx context.Context, client *gift_service.GiftServiceClient) error {
+		return thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "internal")
+	}); err == nil || attempts != 1 {
+		t.Fatalf("期望服务端异常不重试, 实际执行 %d 次: %v", attempts, err)
+	}
+	// 服务端过载拒绝的请求没有被处理，总是重试
+	if attempts, err := call(func(ctx context.Context, client *gift_service.GiftServiceClient) error {
+		return thriftx.NewLoadSheddingException("overloaded")
+	}); err == nil || attempts != policy.MaxAttempts {
+		t.Fatalf("期望服务端过载时重试, 实际执行 %d 次: %v", attempts, err)
+	}
+}
+
+// TestPoolRetrySendGiftOnce 测试 header 传输层上带幂等键的 SendGift 在响应丢失后重试，重试发送相同的幂等键，按幂等键去重的服务端只创建一个礼物
+func TestPoolRetrySendGiftOnce(t *testing.T) {
+	svc := &thrifttest.IdempotentGiftService{}
+	c := &conf.Server_Thrift{Addr: thrifttest.FreeAddr(t), Transport: thriftx.TransportHeader}
+	srv, err := server.NewThriftServer(&conf.Server{Thrift: c}, &thrifttest.UserService{}, svc)
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	thrifttest.Start(t, srv, "", c.Addr)
+
+	// 第一次调用在服务端处理完成后丢弃响应，模拟响应途中连接断开
+	var lost atomic.Bool
+	lost.Store(true)
+	dropReply := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			reply, err := next(ctx, req)
+			if err == nil && lost.CompareAndSwap(true, false) {
+				return nil, thrift.NewTTransportException(thrift.UNKNOWN_TRANSPORT_EXCEPTION, "connection reset by peer")
+			}
+			return reply, err
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = 10 * time.Millisecond
+	gifts := NewPool(c.Addr, "GiftService", gift_service.NewGiftServiceClient, 1, 1, time.Minute,
+		WithTransport(thriftx.TransportHeader), WithRetry(policy), WithMiddleware(dropReply))
+	defer gifts.Close(ctx)
+
+	sendGift := func(key string) (gift *gift_service.Gift, attempts int, err error) {
+		err = gifts.Do(ctx, func(conn *Conn[gift_service.GiftServiceClient]) error {
+			attempts++
+			var err error
+			gift, err = conn.Client.SendGift(WithIdempotencyKey(ctx, key), 1, 2, 10, gift_service.GiftType_GIFT_TYPE_NORMAL, 1)
+			return err
+		})
+		return gift, attempts, err
+	}
+	first, attempts, err := sendGift("gift-once")
+	if err != nil || attempts != 2 {
+		t.Fatalf("期望带幂等键的调用重试后成功, 实际执行 %d 次: %v", attempts, err)
+	}
+	if n := svc.Sends("gift-once"); n != 1 {
+		t.Fatalf("期望服务端只创建 1 个礼物, 实际 %d 个", n)
+	}
+
+	// 相同的幂等键返回第一次创建的礼物，不同的幂等键创建新的礼物
+	if again, _, err := sendGift("gift-once"); err != nil || again.GetGiftId() != first.GetGiftId() {
+		t.Fatalf("期望相同幂等键返回礼物 %d, 实际 %v: %v", first.GetGiftId(), again, err)
+	}
+	if other, _, err := sendGift("gift-twice"); err != nil || other.GetGiftId() == first.GetGiftId() {
+		t.Fatalf("期望新的幂等键创建新的礼物, 实际 %v: %v", other, err)
+	}
+	if n := svc.Sends("gift-once"); n != 1 {
+		t.Fatalf("期望幂等键 gift-once 只创建 1 个礼物, 实际 %d 个", n)
+	}
+}
+
+// TestPoolRetryHandlerError 测试处理函数执行之后返回 503 的非幂等调用不重试
+func TestPoolRetryHandlerError(t *testing.T) {
+	svc := &thrifttest.FailingGiftService{Err: errors.ServiceUnavailable("REDIS", "connection refused")}
+	c := &conf.Server_Thrift{Addr: thrifttest.FreeAddr(t)}
+	srv, err := server.NewThriftServer(&conf.Server{Thrift: c}, &thrifttest.UserService{}, svc)
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	thrifttest.Start(t, srv, "", c.Addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = 10 * time.Millisecond
+	gifts := NewPool(c.Addr, "GiftService", gift_service.NewGiftServiceClient, 1, 1, time.Minute, WithRetry(policy))
+	defer gifts.Close(ctx)
+
+	attempts := 0
+	err = gifts.Do(ctx, func(conn *Conn[gift_service.GiftServiceClient]) error {
+		attempts++
+		_, err := conn.Client.SendGift(ctx, 1, 2, 10, gift_service.GiftType_GIFT_TYPE_NORMAL, 1)
+		return err
+	})
+	var exc thrift.TApplicationException
+	if !stderrors.As(err, &exc) || exc.TypeId() != thrift.INTERNAL_ERROR {
+		t.Fatalf("期望收到 INTERNAL_ERROR 异常, 实际: %v", err)
+	}
+	if n := svc.Calls("SendGift"); attempts != 1 || n != 1 {
+		t.Fatalf("期望处理函数执行之后的 503 不重试, 实际调用 %d 次, 服务端执行 %d 次", attempts, n)
+	}
+}
+
+// TestPoolRetryServerErrors 测试服务端在处理函数执行之前拒绝的请求总是重试，处理函数执行之后的错误即使调用幂等也不重试
+func TestPoolRetryServerErrors(t *testing.T) {
+	svc := &thrifttest.FailingGiftService{Err: errors.ServiceUnavailable("REDIS", "connection refused")}
+	// 模拟限流中间件，sheds 大于 0 时在处理函数执行之前拒绝请求
+	var sheds atomic.Int32
+	ratelimit := func(next middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req any) (any, error) {
+			if sheds.Add(-1) >= 0 {
+				return nil, errors.New(http.StatusTooManyRequests, "RATELIMIT", "rate limit exceeded")
+			}
+			return next(ctx, req)
+		}
+	}
+	c := &conf.Server_Thrift{Addr: thrifttest.FreeAddr(t), Transport: thriftx.TransportHeader}
+	srv, err := server.NewThriftServer(&conf.Server{Thrift: c}, &thrifttest.UserService{}, svc, server.ThriftMiddleware(ratelimit))
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	thrifttest.Start(t, srv, "", c.Addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	policy := DefaultRetryPolicy()
+	policy.InitialBackoff = 10 * time.Millisecond
+	gifts := NewPool(c.Addr, "GiftService", gift_service.NewGiftServiceClient, 1, 1, time.Minute,
+		WithTransport(thriftx.TransportHeader), WithRetry(policy))
+	defer gifts.Close(ctx)
+
+	call := func(fn func(client *gift_service.GiftServiceClient) error) (int, error) {
+		attempts := 0
+		err := gifts.Do(ctx, func(conn *Conn[gift_service.GiftServiceClient]) error {
+			attempts++
+			return fn(conn.Client)
+		})
+		return attempts, err
+	}
+	isInternalError := func(err error) bool {
+		var exc thrift.TApplicationException
+		return stderrors.As(err, &exc) && exc.TypeId() == thrift.INTERNAL_ERROR
+	}
+	sendGift := func(client *gift_service.GiftServiceClient) error {
+		_, err := client.SendGift(ctx, 1, 2, 10, gift_service.GiftType_GIFT_TYPE_NORMAL, 1)
+		return err
+	}
+
+	// 非幂等的调用被限流时处理函数没有执行，重试后处理函数执行一次，之后的 503 不再重试
+	sheds.Store(1)
+	if attempts, err := call(sendGift); !isInternalError(err) || attempts != 2 {
+		t.Fatalf("期望限流后重试一次, 实际执行 %d 次: %v", attempts, err)
+	}
+	if n := svc.Calls("SendGift"); n != 1 {
+		t.Fatalf("期望处理函数执行 1 次, 实际 %d 次", n)
+	}
+
+	// 处理函数执行之后的 503，幂等的调用与带幂等键的调用同样不重试
+	if attempts, err := call(func(client *gift_service.GiftServiceClient) error {
+		_, err := client.GetGiftsBySender(ctx, 1)
+		return err
+	}); !isInternalError(err) || attempts != 1 {
+		t.Fatalf("期望幂等的调用不重试, 实际执行 %d 次: %v", attempts, err)
+	}
+	if n := svc.Calls("GetGiftsBySender"); n != 1 {
+		t.Fatalf("期望处理函数执行 1 次, 实际 %d 次", n)
+	}
+	if attempts, err := call(func(client *gift_service.GiftServiceClient) error {
+		_, err := client.SendGift(WithIdempotencyKey(ctx, "gift-1"), 1, 2, 10, gift_service.GiftType_GIFT_TYPE_NORMAL, 1)
+		return err
+	}); !isInternalError(err) || attempts != 1 {
+		t.Fatalf("期望带幂等键的调用不重试, 实际执行 %d 次: %v", attempts, err)
+	}
+	if n := svc.Calls("SendGift"); n != 2 {
+		t.Fatalf("期望处理函数共执行 2 次, 实际 %d 次", n)
+	}
+}
+
+// TestRetryPolicyBackoff 测试退避时间按倍数增长，不超过上限，抖动只减少等待时间
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+	for retry, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		if got := policy.backoff(retry + 1); got != want {
+			t.Errorf("第 %d 次重试等待 %v, 期望 %v", retry+1, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(2); got < 100*time.Millisecond || got > 200*time.Millisecond {
+			t.Fatalf("抖动后的等待时间 %v 超出 [100ms, 200ms]", got)
+		}
+	}
+}
@@ -2,105 +2,336 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"sync/atomic"
 	"time"
 
 	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/pkg/health"
+	"aboveThriftRPC/internal/pkg/metrics"
+	"aboveThriftRPC/internal/pkg/thriftx"
 
 	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/middleware/tracing"
+	"github.com/go-kratos/kratos/v2/selector"
 	pool "github.com/jolestar/go-commons-pool/v2"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	// defaultMaxMessageSize 默认的单条消息与单帧的最大字节数
+	defaultMaxMessageSize = 16 * 1024 * 1024 // 16 MB
+	// defaultProbeTimeout 未设置探测超时时间时使用的默认值
+	defaultProbeTimeout = time.Second
+)
+
 type ThriftClient struct {
-	addr string
+	network        string
+	addr           string
+	protocol       string
+	transport      string
+	tlsConfig      *tls.Config
+	middleware     []middleware.Middleware
+	maxMessageSize int32
+	maxFrameSize   int32
+	probeInterval  time.Duration
+	probeTimeout   time.Duration
+	retry          *RetryPolicy
+	selector       selector.Builder
+	nodeFilters    []selector.NodeFilter
+}
+
+// Option ThriftClient 选项
+type Option func(*ThriftClient)
+
+// WithNetwork 设置拨号的网络类型: tcp（默认）、tcp4、tcp6、unix，unix 时 addr 为 socket 文件路径
+func WithNetwork(network string) Option {
+	return func(c *ThriftClient) {
+		c.network = network
+	}
+}
+
+// WithProtocol 设置客户端协议: binary（默认）、compact、json、header
+func WithProtocol(protocol string) Option {
+	return func(c *ThriftClient) {
+		c.protocol = protocol
+	}
+}
+
+// WithTransport 设置客户端传输层: buffered（默认）、framed、header，需与服务端 conf.Server.Thrift.Transport 一致
+func WithTransport(transport string) Option {
+	return func(c *ThriftClient) {
+		c.transport = transport
+	}
+}
+
+// WithTLSConfig 使用 TLS 拨号，配置客户端证书即为 mTLS
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *ThriftClient) {
+		c.tlsConfig = tlsConfig
+	}
+}
+
+// WithMaxMessageSize 设置读取响应时单条消息与单个字段的最大字节数，默认 16MB
+func WithMaxMessageSize(n int32) Option {
+	return func(c *ThriftClient) {
+		c.maxMessageSize = n
+	}
+}
+
+// WithMaxFrameSize 设置 framed、header 传输层单帧的最大字节数，默认 16MB
+func WithMaxFrameSize(n int32) Option {
+	return func(c *ThriftClient) {
+		c.maxFrameSize = n
+	}
+}
+
+// WithProbe 开启连接的主动探测：空闲超过 interval 的连接在借出时与驱逐检测时调用 Health.Check，
+// 超过 timeout（默认 1s）没有响应、调用失败或服务端返回 NOT_SERVING 时销毁连接，驱逐检测每隔 interval 运行一次。
+// 服务端需要注册 Health 服务；未开启时只做非阻塞的连接检查，无法发现半开的连接。
+func WithProbe(interval, timeout time.Duration) Option {
+	return func(c *ThriftClient) {
+		c.probeInterval = interval
+		c.probeTimeout = timeout
+	}
+}
+
+// WithMiddleware 追加客户端的 kratos 中间件，按添加顺序由外到内执行，与服务端的 ThriftMiddleware 一致，
+// 中间件可以通过 transport.FromClientContext 取到 thrift transport
+func WithMiddleware(m ...middleware.Middleware) Option {
+	return func(c *ThriftClient) {
+		c.middleware = append(c.middleware, m...)
+	}
 }
 
 // NewThriftClient 创建新的 ThriftClient
-func NewThriftClient(addr string) *ThriftClient {
-	return &ThriftClient{
-		addr: addr,
+func NewThriftClient(addr string, opts ...Option) *ThriftClient {
+	c := &ThriftClient{
+		addr:           addr,
+		maxMessageSize: defaultMaxMessageSize,
+		maxFrameSize:   defaultMaxMessageSize,
+	}
+	for _, o := range opts {
+		o(c)
 	}
+	return c
 }
 
-// MakeObject 创建一个新的 Thrift 客户端连接
-func (f *ThriftClient) MakeObject(ctx context.Context) (*pool.PooledObject, error) {
+// open 按选项拨号并打开传输层，返回底层 socket、传输层、协议与服务端地址
+func (f *ThriftClient) open() (thrift.TTransport, thrift.TTransport, thrift.TProtocol, string, error) {
+	cfg := &thrift.TConfiguration{
+		MaxMessageSize: f.maxMessageSize,
+		MaxFrameSize:   f.maxFrameSize,
+		TLSConfig:      f.tlsConfig,
+	}
+
+	// 根据选项创建传输层与协议，默认 buffered + binary
+	transportFactory, protocolFactory, err := thriftx.NewFactories(f.protocol, f.transport, cfg)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+
 	// 创建socket
-	socket := thrift.NewTSocketConf(f.addr, &thrift.TConfiguration{
-		MaxMessageSize: 16 * 1024 * 1024,
-	})
+	addr, err := thriftx.NewAddr(f.network, f.addr)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+	endpoint, err := thriftx.NewEndpoint(addr.Net, addr.Address, f.tlsConfig != nil)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
+	var socket thrift.TTransport
+	if f.tlsConfig != nil {
+		socket = thrift.NewTSSLSocketFromAddrConf(addr, cfg)
+	} else {
+		socket = thrift.NewTSocketFromAddrConf(addr, cfg)
+	}
 
-	// 创建缓冲传输
-	transport := thrift.NewTBufferedTransport(socket, 2048)
+	// 创建传输层
+	transport, err := transportFactory.GetTransport(socket)
+	if err != nil {
+		return nil, nil, nil, "", err
+	}
 
-	// 创建二进制协议
-	protocol := thrift.NewTBinaryProtocolConf(transport, &thrift.TConfiguration{
-		MaxMessageSize: 16 * 1024 * 1024,
-	})
+	// 创建协议
+	protocol := protocolFactory.GetProtocol(transport)
 
 	// 打开传输
 	if err := transport.Open(); err != nil {
+		return nil, nil, nil, "", err
+	}
+	return socket, transport, protocol, endpoint.String(), nil
+}
+
+// middlewares 客户端 span 在最外层，W3C trace context 通过 THeader 请求头传给服务端
+func (f *ThriftClient) middlewares() []middleware.Middleware {
+	return append([]middleware.Middleware{tracing.Client()}, f.middleware...)
+}
+
+// clientFactory 创建 service 服务的客户端连接，newClient 为生成的客户端构造函数
+type clientFactory[T any] struct {
+	*ThriftClient
+	service   string
+	newClient func(thrift.TClient) *T
+	// idempotent 传输层出错后可以重试的方法，键为 Service.method
+	idempotent map[string]bool
+}
+
+// MakeObject 创建一个新的 Thrift 客户端连接
+func (f *clientFactory[T]) MakeObject(ctx context.Context) (*pool.PooledObject, error) {
+	socket, transport, protocol, endpoint, err := f.open()
+	if err != nil {
 		return nil, err
 	}
 
 	// 创建多路协议
-	multiplexedProtocol := thrift.NewTMultiplexedProtocol(protocol, "UserService")
-	multiplexedInputProtocol := thrift.NewTMultiplexedProtocol(protocol, "UserService")
+	multiplexedProtocol := thrift.NewTMultiplexedProtocol(protocol, f.service)
+	multiplexedInputProtocol := thrift.NewTMultiplexedProtocol(protocol, f.service)
 
-	// 创建客户端
-	client := user_service.NewUserServiceClient(thrift.NewTStandardClient(multiplexedInputProtocol, multiplexedProtocol))
+	// 创建客户端，THeader 协议时透传请求头与响应头
+	headerProtocol, _ := protocol.(*thrift.THeaderProtocol)
+	calls := new(callLog)
+	client := f.newClient(thrift.WrapClient(
+		thrift.NewTStandardClient(multiplexedInputProtocol, multiplexedProtocol),
+		callLogMiddleware(f.service, f.idempotent, headerProtocol != nil, calls),
+		metricsMiddleware(f.service),
+		transportMiddleware(endpoint, f.service, headerProtocol, middleware.Chain(f.middlewares()...)),
+	))
 
 	// 创建连接对象
-	conn := &ThriftClientConn{
+	conn := &Conn[T]{
 		Transport: transport,
 		Client:    client,
+		socket:    socket,
+		calls:     calls,
+		// 探测调用同一个连接上的 Health 服务，不经过中间件
+		health: health.NewClientProtocol(protocol),
+		header: headerProtocol,
 	}
 
 	return pool.NewPooledObject(conn), nil
 }
 
 // DestroyObject 销毁 Thrift 客户端连接
-func (f *ThriftClient) DestroyObject(ctx context.Context, object *pool.PooledObject) error {
-	if conn, ok := object.Object.(*ThriftClientConn); ok {
+func (f *clientFactory[T]) DestroyObject(ctx context.Context, object *pool.PooledObject) error {
+	if conn, ok := object.Object.(*Conn[T]); ok {
 		return conn.Transport.Close()
 	}
 	return nil
 }
 
-// ValidateObject 验证 Thrift 客户端连接是否有效
-func (f *ThriftClient) ValidateObject(ctx context.Context, object *pool.PooledObject) bool {
-	if conn, ok := object.Object.(*ThriftClientConn); ok {
-		return conn.Transport.IsOpen()
+// ValidateObject 验证 Thrift 客户端连接是否有效，借出、创建与驱逐检测时调用
+//
+// socket 的 IsOpen 以非阻塞的方式读取连接，服务端已经关闭的连接返回 false；
+// 开启探测时空闲超过 probeInterval 的连接再调用一次 Health.Check。
+func (f *clientFactory[T]) ValidateObject(ctx context.Context, object *pool.PooledObject) bool {
+	conn, ok := object.Object.(*Conn[T])
+	if !ok || !conn.socket.IsOpen() {
+		return false
 	}
-	return false
+	if f.probeInterval <= 0 {
+		return true
+	}
+	last := object.LastReturnTime
+	if conn.probed.After(last) {
+		last = conn.probed
+	}
+	if time.Since(last) < f.probeInterval {
+		return true
+	}
+	return f.probe(ctx, conn)
+}
+
+// probe 调用 Health.Check 探测连接，超时后关闭 socket
+func (f *clientFactory[T]) probe(ctx context.Context, conn *Conn[T]) bool {
+	timeout := f.probeTimeout
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	if ctx == nil {
+		// 驱逐线程调用时 ctx 为 nil
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	stop := context.AfterFunc(ctx, func() { conn.socket.Close() })
+	defer stop()
+
+	// 上一次调用的请求头还留在 THeader 协议上，不能随探测发出，也不能留给下一次调用
+	if conn.header != nil {
+		conn.header.ClearWriteHeaders()
+		defer conn.header.ClearWriteHeaders()
+	}
+	resp, err := conn.health.Check(ctx, f.service)
+	conn.probed = time.Now()
+	if err != nil {
+		logrus.Warnf("probe thrift client connection to %s error: %v", f.addr, err)
+		return false
+	}
+	return resp.Status != health.ServingStatus_NOT_SERVING
 }
 
 // ActivateObject 激活 Thrift 客户端连接
-func (f *ThriftClient) ActivateObject(ctx context.Context, object *pool.PooledObject) error {
+func (f *clientFactory[T]) ActivateObject(ctx context.Context, object *pool.PooledObject) error {
 	return nil
 }
 
 // PassivateObject 钝化 Thrift 客户端连接
-func (f *ThriftClient) PassivateObject(ctx context.Context, object *pool.PooledObject) error {
+func (f *clientFactory[T]) PassivateObject(ctx context.Context, object *pool.PooledObject) error {
 	// 不需要特殊处理
 	return nil
 }
 
-// ThriftClientConn 封装客户端连接信息
-type ThriftClientConn struct {
+// Conn 封装客户端连接信息，Client 为生成的客户端，如 *gift_service.GiftServiceClient
+type Conn[T any] struct {
 	Transport thrift.TTransport
-	Client    *user_service.UserServiceClient
+	Client    *T
+
+	// socket Transport 底层的 socket，可以在调用过程中并发关闭
+	socket thrift.TTransport
+	// calls 记录一次 Do 中发起的调用，用于判断能否重试
+	calls *callLog
+	// health、header 与 probed 用于主动探测，只在连接没有借出时使用
+	health *health.Client
+	header *thrift.THeaderProtocol
+	probed time.Time
 }
 
-// ThriftConnectionPool 基于 go-commons-pool 的 Thrift 连接池
-type ThriftConnectionPool struct {
-	pool *pool.ObjectPool
+// ThriftClientConn UserService 的客户端连接
+type ThriftClientConn = Conn[user_service.UserServiceClient]
+
+// Pool 基于 go-commons-pool 的 Thrift 连接池，T 为生成的客户端类型，如 gift_service.GiftServiceClient
+type Pool[T any] struct {
+	pool  *pool.ObjectPool
+	addr  string
+	retry *RetryPolicy
+
+	// waiters 正在等待借出连接的调用数，go-commons-pool 没有提供
+	waiters    atomic.Int64
+	unregister func()
 }
 
-// NewThriftConnectionPool 创建新的 Thrift 连接池
-func NewThriftConnectionPool(addr string, maxIdle, maxActive int, idleTimeout time.Duration) *ThriftConnectionPool {
+// ThriftConnectionPool UserService 的连接池
+type ThriftConnectionPool = Pool[user_service.UserServiceClient]
+
+// NewPool 创建 service 服务的连接池，连接通过多路协议调用 service，newClient 为生成的客户端构造函数，例如：
+//
+//	NewPool(addr, "GiftService", gift_service.NewGiftServiceClient, maxIdle, maxActive, idleTimeout)
+func NewPool[T any](addr, service string, newClient func(thrift.TClient) *T, maxIdle, maxActive int, idleTimeout time.Duration, opts ...Option) *Pool[T] {
 	ctx := context.Background()
-	factory := NewThriftClient(addr)
+	factory := &clientFactory[T]{
+		ThriftClient: NewThriftClient(addr, opts...),
+		service:      service,
+		newClient:    newClient,
+		idempotent:   make(map[string]bool),
+	}
+	if retry := factory.retry; retry != nil {
+		for _, method := range retry.IdempotentMethods {
+			factory.idempotent[method] = true
+		}
+	}
 
 	// 创建对象池
 	p := pool.NewObjectPool(ctx, factory, &pool.ObjectPoolConfig{
@@ -115,24 +346,47 @@ func NewThriftConnectionPool(addr string, maxIdle, maxActive int, idleTimeout ti
 		TimeBetweenEvictionRuns: idleTimeout / 2, // 驱逐线程运行间隔
 		NumTestsPerEvictionRun:  3,               // 每次驱逐线程检测的连接数
 	})
+	if interval := factory.probeInterval; interval > 0 {
+		// 开启探测时驱逐线程每隔 interval 检测全部空闲连接
+		if interval < p.Config.TimeBetweenEvictionRuns || p.Config.TimeBetweenEvictionRuns <= 0 {
+			p.Config.TimeBetweenEvictionRuns = interval
+		}
+		p.Config.NumTestsPerEvictionRun = -1
+	}
 
 	// 启动驱逐器
 	p.StartEvictor()
 
-	return &ThriftConnectionPool{
-		pool: p,
+	cp := &Pool[T]{
+		pool:  p,
+		addr:  addr,
+		retry: factory.retry,
 	}
+	// 采集活跃、空闲与等待中的连接数，由 /metrics 输出
+	cp.unregister = metrics.RegisterPool(addr, func() (int, int, int) {
+		return p.GetNumActive(), p.GetNumIdle(), int(cp.waiters.Load())
+	})
+	return cp
+}
+
+// NewThriftConnectionPool 创建 UserService 的连接池
+func NewThriftConnectionPool(addr string, maxIdle, maxActive int, idleTimeout time.Duration, opts ...Option) *ThriftConnectionPool {
+	return NewPool(addr, "UserService", user_service.NewUserServiceClient, maxIdle, maxActive, idleTimeout, opts...)
 }
 
 // GetConnection 从连接池获取连接
-func (p *ThriftConnectionPool) GetConnection(ctx context.Context) (*ThriftClientConn, error) {
+func (p *Pool[T]) GetConnection(ctx context.Context) (*Conn[T], error) {
+	p.waiters.Add(1)
+	start := time.Now()
 	obj, err := p.pool.BorrowObject(ctx)
+	p.waiters.Add(-1)
+	metrics.ClientPoolBorrowWait.WithLabelValues(p.addr).Observe(time.Since(start).Seconds())
 	if err != nil {
 		logrus.Errorf("borrow thrift client connection error: %v, obj: %v", err, obj)
 		return nil, err
 	}
 
-	conn, ok := obj.(*ThriftClientConn)
+	conn, ok := obj.(*Conn[T])
 	if !ok {
 		logrus.Errorf("invalid thrift client connection type: %T, error: %v", obj, err)
 		return nil, errors.New("invalid thrift client connection")
@@ -141,7 +395,7 @@ func (p *ThriftConnectionPool) GetConnection(ctx context.Context) (*ThriftClient
 }
 
 // ReleaseConnection 释放连接回连接池
-func (p *ThriftConnectionPool) ReleaseConnection(ctx context.Context, conn *ThriftClientConn) error {
+func (p *Pool[T]) ReleaseConnection(ctx context.Context, conn *Conn[T]) error {
 	err := p.pool.ReturnObject(ctx, conn)
 	if err != nil {
 		logrus.Errorf("return thrift client connection error: %v, conn: %v", err, conn)
@@ -151,7 +405,7 @@ func (p *ThriftConnectionPool) ReleaseConnection(ctx context.Context, conn *Thri
 }
 
 // CloseConnection 关闭指定连接
-func (p *ThriftConnectionPool) CloseConnection(ctx context.Context, conn *ThriftClientConn) error {
+func (p *Pool[T]) CloseConnection(ctx context.Context, conn *Conn[T]) error {
 	err := p.pool.InvalidateObject(ctx, conn)
 	if err != nil {
 		logrus.Errorf("close thrift client connection error: %v, conn: %v", err, conn)
@@ -160,8 +414,74 @@ func (p *ThriftConnectionPool) CloseConnection(ctx context.Context, conn *Thrift
 	return nil
 }
 
+// Do 借出一个连接执行 fn，fn 返回后自动归还或销毁连接
+//
+// ctx 到期时关闭底层 socket，阻塞中的读写立即返回，超时的连接不会再被复用。
+// fn 返回传输层或协议错误、响应与请求不匹配时，连接上可能残留未读的响应，同样销毁；fn panic 时也销毁连接。
+// 其余情况（包括服务端返回的异常）连接归还连接池。返回 fn 的错误。
+//
+// 开启 WithRetry 时，建立连接失败、服务端过载拒绝，以及 fn 中的调用全部为幂等时的传输层错误，
+// 按退避时间等待后借出新的连接重新执行 fn，fn 可能被执行多次。
+func (p *Pool[T]) Do(ctx context.Context, fn func(conn *Conn[T]) error) error {
+	return withRetry(ctx, p.retry, func() (string, callLog, bool, error) {
+		calls, borrowed, err := p.do(ctx, fn)
+		return p.addr, calls, borrowed, err
+	})
+}
+
+// do 借出一个连接执行一次 fn，返回 fn 中发起的调用，borrowed 为 false 时没有借出连接
+func (p *Pool[T]) do(ctx context.Context, fn func(conn *Conn[T]) error) (calls callLog, borrowed bool, err error) {
+	conn, err := p.GetConnection(ctx)
+	if err != nil {
+		return callLog{}, false, err
+	}
+	*conn.calls = callLog{}
+	stop := context.AfterFunc(ctx, func() { conn.socket.Close() })
+	broken := true
+	defer func() {
+		// 归还前复制调用记录，连接归还后可能被其他调用借出
+		calls = *conn.calls
+		// ctx 到期后归还或销毁连接不应失败
+		ctx := context.WithoutCancel(ctx)
+		if !stop() || broken {
+			p.CloseConnection(ctx, conn)
+		} else {
+			p.ReleaseConnection(ctx, conn)
+		}
+	}()
+	err = fn(conn)
+	broken = err != nil && isBrokenConn(err)
+	return callLog{}, true, err
+}
+
+// isBrokenConn 判断调用失败后连接是否不能继续使用
+func isBrokenConn(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return true
+	}
+	var transportErr thrift.TTransportException
+	if errors.As(err, &transportErr) {
+		return true
+	}
+	var protocolErr thrift.TProtocolException
+	if errors.As(err, &protocolErr) {
+		return true
+	}
+	var appErr thrift.TApplicationException
+	if errors.As(err, &appErr) {
+		switch appErr.TypeId() {
+		case thrift.BAD_SEQUENCE_ID, thrift.WRONG_METHOD_NAME, thrift.INVALID_MESSAGE_TYPE_EXCEPTION, thrift.PROTOCOL_ERROR,
+			// 请求体没有读完时服务端返回异常后关闭连接
+			thriftx.REQUEST_TOO_LARGE:
+			return true
+		}
+	}
+	return false
+}
+
 // Close 关闭连接池
-func (p *ThriftConnectionPool) Close(ctx context.Context) error {
+func (p *Pool[T]) Close(ctx context.Context) error {
+	p.unregister()
 	p.pool.Close(ctx)
 	return nil
 }
@@ -0,0 +1,493 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/pkg/thriftx"
+	"aboveThriftRPC/internal/server"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// startServer 按配置启动 thrift 服务端，地址为空时使用本地空闲端口，返回监听地址
+func startServer(t *testing.T, c *conf.Server_Thrift, opts ...server.ThriftServerOption) string {
+	t.Helper()
+	if c.Addr == "" {
+		c.Addr = thrifttest.FreeAddr(t)
+	}
+	srv, err := server.NewThriftServer(&conf.Server{Thrift: c}, &thrifttest.UserService{}, &thrifttest.GiftService{}, opts...)
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	thrifttest.Start(t, srv, c.Network, c.Addr)
+	return c.Addr
+}
+
+// newGiftClient 使用与连接池相同的工厂创建 GiftService 客户端
+func newGiftClient(t *testing.T, addr, protocol, transport string) *gift_service.GiftServiceClient {
+	t.Helper()
+	transportFactory, protocolFactory, err := thriftx.NewFactories(protocol, transport, nil)
+	if err != nil {
+		t.Fatalf("创建工厂失败: %v", err)
+	}
+	trans, err := transportFactory.GetTransport(thrift.NewTSocketConf(addr, nil))
+	if err != nil {
+		t.Fatalf("创建传输层失败: %v", err)
+	}
+	if err := trans.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	t.Cleanup(func() {
+		trans.Close()
+	})
+	prot := thrift.NewTMultiplexedProtocol(protocolFactory.GetProtocol(trans), "GiftService")
+	return gift_service.NewGiftServiceClient(thrift.NewTStandardClient(prot, prot))
+}
+
+// TestThriftTransportCombinations 测试服务端与连接池在各种传输层、协议组合下的往返调用
+func TestThriftTransportCombinations(t *testing.T) {
+	cases := []struct {
+		transport string
+		protocol  string
+	}{
+		{thriftx.TransportBuffered, thriftx.ProtocolBinary},
+		{thriftx.TransportBuffered, thriftx.ProtocolCompact},
+		{thriftx.TransportBuffered, thriftx.ProtocolJSON},
+		{thriftx.TransportFramed, thriftx.ProtocolBinary},
+		{thriftx.TransportFramed, thriftx.ProtocolCompact},
+		{thriftx.TransportFramed, thriftx.ProtocolJSON},
+		{thriftx.TransportHeader, thriftx.ProtocolBinary},
+		{thriftx.TransportHeader, thriftx.ProtocolCompact},
+	}
+	for _, c := range cases {
+		t.Run(c.transport+"/"+c.protocol, func(t *testing.T) {
+			addr := startServer(t, &conf.Server_Thrift{Protocol: c.protocol, Transport: c.transport})
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			pool := NewThriftConnectionPool(addr, 2, 4, time.Minute, WithProtocol(c.protocol), WithTransport(c.transport))
+			defer pool.Close(ctx)
+			conn, err := pool.GetConnection(ctx)
+			if err != nil {
+				t.Fatalf("获取连接失败: %v", err)
+			}
+			resp, err := conn.Client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1})
+			if err != nil {
+				t.Fatalf("调用 EchoData 失败: %v", err)
+			}
+			if string(resp.ClientData) != "hello" {
+				t.Fatalf("EchoData 返回数据不一致: %q", resp.ClientData)
+			}
+			pool.ReleaseConnection(ctx, conn)
+
+			gift, err := newGiftClient(t, addr, c.protocol, c.transport).SendGift(ctx, 1, 2, 10, gift_service.GiftType_GIFT_TYPE_NORMAL, 3)
+			if err != nil {
+				t.Fatalf("调用 SendGift 失败: %v", err)
+			}
+			if gift.SenderId != 1 || gift.ReceiverId != 2 || gift.Quantity != 3 {
+				t.Fatalf("SendGift 返回数据不一致: %v", gift)
+			}
+		})
+	}
+}
+
+// TestThriftAutoServerWithPool 测试 auto 服务端同时接受不同传输层与协议的连接池
+func TestThriftAutoServerWithPool(t *testing.T) {
+	addr := startServer(t, &conf.Server_Thrift{Protocol: thriftx.ProtocolAuto})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, opts := range [][]Option{
+		{WithProtocol(thriftx.ProtocolBinary)},
+		{WithProtocol(thriftx.ProtocolCompact), WithTransport(thriftx.TransportFramed)},
+		{WithTransport(thriftx.TransportHeader)},
+	} {
+		pool := NewThriftConnectionPool(addr, 1, 1, time.Minute, opts...)
+		conn, err := pool.GetConnection(ctx)
+		if err != nil {
+			t.Fatalf("获取连接失败: %v", err)
+		}
+		if _, err := conn.Client.EchoData(ctx, []byte("auto"), &user_service.User{ID: 1}); err != nil {
+			t.Fatalf("调用 EchoData 失败: %v", err)
+		}
+		pool.ReleaseConnection(ctx, conn)
+		pool.Close(ctx)
+	}
+}
+
+// TestThriftPoolUnixSocket 测试连接池通过 unix socket 拨号
+func TestThriftPoolUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thrift.sock")
+	startServer(t, &conf.Server_Thrift{Network: thriftx.NetworkUnix, Addr: path})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pool := NewThriftConnectionPool(path, 1, 1, time.Minute, WithNetwork(thriftx.NetworkUnix))
+	defer pool.Close(ctx)
+	conn, err := pool.GetConnection(ctx)
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	if _, err := conn.Client.EchoData(ctx, []byte("unix"), &user_service.User{ID: 1}); err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	pool.ReleaseConnection(ctx, conn)
+
+	// 不支持的网络类型在建立连接时返回错误
+	bad := NewThriftConnectionPool(path, 1, 1, time.Minute, WithNetwork("udp"))
+	defer bad.Close(ctx)
+	if _, err := bad.GetConnection(ctx); err == nil {
+		t.Fatal("期望不支持的网络类型返回错误")
+	}
+}
+
+// TestPoolServices 测试同一个多路复用服务端上分别为 UserService 与 GiftService 创建连接池
+func TestPoolServices(t *testing.T) {
+	addr := startServer(t, &conf.Server_Thrift{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	gifts := NewPool(addr, "GiftService", gift_service.NewGiftServiceClient, 2, 4, time.Minute)
+	defer gifts.Close(ctx)
+	users := NewPool[user_service.UserServiceClient](addr, "UserService", user_service.NewUserServiceClient, 2, 4, time.Minute)
+	defer users.Close(ctx)
+
+	giftConn, err := gifts.GetConnection(ctx)
+	if err != nil {
+		t.Fatalf("获取 GiftService 连接失败: %v", err)
+	}
+	gift, err := giftConn.Client.SendGift(ctx, 1, 2, 10, gift_service.GiftType_GIFT_TYPE_NORMAL, 3)
+	if err != nil {
+		t.Fatalf("调用 SendGift 失败: %v", err)
+	}
+	if gift.SenderId != 1 || gift.ReceiverId != 2 || gift.Quantity != 3 {
+		t.Fatalf("SendGift 返回数据不一致: %v", gift)
+	}
+	senders, err := giftConn.Client.GetTop10Senders(ctx)
+	if err != nil || len(senders) != 3 {
+		t.Fatalf("调用 GetTop10Senders 失败: %v, %v", senders, err)
+	}
+	gifts.ReleaseConnection(ctx, giftConn)
+
+	userConn, err := users.GetConnection(ctx)
+	if err != nil {
+		t.Fatalf("获取 UserService 连接失败: %v", err)
+	}
+	resp, err := userConn.Client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1})
+	if err != nil || string(resp.ClientData) != "hello" {
+		t.Fatalf("调用 EchoData 失败: %v, %v", resp, err)
+	}
+	users.ReleaseConnection(ctx, userConn)
+
+	// 连接复用：再次借出的是同一个连接
+	again, err := gifts.GetConnection(ctx)
+	if err != nil {
+		t.Fatalf("获取 GiftService 连接失败: %v", err)
+	}
+	if again != giftConn {
+		t.Error("期望复用空闲连接")
+	}
+	if _, err := again.Client.GetGiftsBySender(ctx, 1); err != nil {
+		t.Fatalf("调用 GetGiftsBySender 失败: %v", err)
+	}
+	gifts.ReleaseConnection(ctx, again)
+}
+
+// TestPoolDo 测试 Do 归还正常的连接，销毁传输层出错与超时的连接
+func TestPoolDo(t *testing.T) {
+	addr := startServer(t, &conf.Server_Thrift{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pool := NewThriftConnectionPool(addr, 2, 2, time.Minute)
+	defer pool.Close(ctx)
+
+	echo := func(ctx context.Context, data string) (*ThriftClientConn, error) {
+		var used *ThriftClientConn
+		err := pool.Do(ctx, func(conn *ThriftClientConn) error {
+			used = conn
+			_, err := conn.Client.EchoData(ctx, []byte(data), &user_service.User{ID: 1})
+			return err
+		})
+		return used, err
+	}
+
+	first, err := echo(ctx, "hello")
+	if err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+	// 服务端返回的异常不影响连接
+	if used, err := echo(ctx, "panic"); err == nil || used != first {
+		t.Fatalf("期望服务端异常后复用连接, 实际: %v, 同一连接 %v", err, used == first)
+	}
+	if used, err := echo(ctx, "hello"); err != nil || used != first {
+		t.Fatalf("期望复用连接, 实际: %v, 同一连接 %v", err, used == first)
+	}
+
+	// 传输层错误后销毁连接
+	broken := thrift.NewTTransportException(thrift.UNKNOWN_TRANSPORT_EXCEPTION, "broken")
+	if err := pool.Do(ctx, func(conn *ThriftClientConn) error { return broken }); err != broken {
+		t.Fatalf("期望返回 fn 的错误, 实际: %v", err)
+	}
+	if n := pool.pool.GetNumIdle(); n != 0 {
+		t.Fatalf("期望传输层错误后连接被销毁, 空闲连接数: %d", n)
+	}
+
+	// 超时的调用立即返回，连接不再复用
+	second, err := echo(ctx, "hello")
+	if err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer timeoutCancel()
+	start := time.Now()
+	used, err := echo(timeoutCtx, "slow")
+	if err == nil {
+		t.Fatal("期望超时的调用失败")
+	}
+	if d := time.Since(start); d > 250*time.Millisecond {
+		t.Errorf("超时的调用没有及时返回: %v", d)
+	}
+	if used != second {
+		t.Fatal("期望超时的调用使用空闲连接")
+	}
+	if n := pool.pool.GetNumIdle(); n != 0 {
+		t.Fatalf("期望超时后连接被销毁, 空闲连接数: %d", n)
+	}
+	if used, err := echo(ctx, "hello"); err != nil || used == second {
+		t.Fatalf("期望超时后使用新连接, 实际: %v, 同一连接 %v", err, used == second)
+	}
+}
+
+// TestIsBrokenConn 测试哪些错误会让连接不能继续使用
+func TestIsBrokenConn(t *testing.T) {
+	cases := []struct {
+		err    error
+		broken bool
+	}{
+		{thrift.NewTTransportException(thrift.TIMED_OUT, "timeout"), true},
+		{thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, errors.New("bad data")), true},
+		{thrift.NewTApplicationException(thrift.BAD_SEQUENCE_ID, "out of sequence"), true},
+		{thriftx.NewRequestTooLargeException("too large"), true},
+		{context.DeadlineExceeded, true},
+		{thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "internal"), false},
+		{thriftx.NewTimeoutException("timeout"), false},
+		{errors.New("business error"), false},
+	}
+	for _, c := range cases {
+		if got := isBrokenConn(c.err); got != c.broken {
+			t.Errorf("isBrokenConn(%v) = %v, 期望 %v", c.err, got, c.broken)
+		}
+	}
+}
+
+// testProxy 转发到服务端的 TCP 代理，可以冻结已有的连接模拟半开连接，或关闭全部连接
+type testProxy struct {
+	mu     sync.Mutex
+	conns  []net.Conn
+	frozen map[net.Conn]*atomic.Bool
+	// resets 之后收到请求时直接断开连接的次数，请求不会转发给服务端
+	resets atomic.Int32
+}
+
+// startProxy 启动转发到 upstream 的代理，返回代理与监听地址
+func startProxy(t *testing.T, upstream string) (*testProxy, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	p := &testProxy{frozen: make(map[net.Conn]*atomic.Bool)}
+	t.Cleanup(func() {
+		l.Close()
+		p.closeAll()
+	})
+	go func() {
+		for {
+			down, err := l.Accept()
+			if err != nil {
+				return
+			}
+			up, err := net.Dial("tcp", upstream)
+			if err != nil {
+				down.Close()
+				continue
+			}
+			frozen := new(atomic.Bool)
+			p.mu.Lock()
+			p.conns = append(p.conns, down, up)
+			p.frozen[down] = frozen
+			p.mu.Unlock()
+			go func() {
+				// 冻结后丢弃客户端发送的数据，服务端不再响应
+				buf := make([]byte, 4096)
+				for {
+					n, err := down.Read(buf)
+					if err != nil {
+						up.Close()
+						return
+					}
+					if n := p.resets.Load(); n > 0 && p.resets.CompareAndSwap(n, n-1) {
+						down.Close()
+						up.Close()
+						return
+					}
+					if !frozen.Load() {
+						if _, err := up.Write(buf[:n]); err != nil {
+							down.Close()
+							return
+						}
+					}
+				}
+			}()
+			go func() {
+				io.Copy(down, up)
+				down.Close()
+			}()
+		}
+	}()
+	return p, l.Addr().String()
+}
+
+// freeze 冻结当前已有的连接，之后建立的连接不受影响
+func (p *testProxy) freeze() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, frozen := range p.frozen {
+		frozen.Store(true)
+	}
+}
+
+// closeAll 关闭当前已有的连接
+func (p *testProxy) closeAll() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, c := range p.conns {
+		c.Close()
+	}
+	p.conns = nil
+	p.frozen = make(map[net.Conn]*atomic.Bool)
+}
+
+// TestPoolProbe 测试探测销毁服务端已经关闭的连接与没有响应的半开连接
+func TestPoolProbe(t *testing.T) {
+	upstream := startServer(t, &conf.Server_Thrift{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	echo := func(pool *ThriftConnectionPool) (*ThriftClientConn, error) {
+		var used *ThriftClientConn
+		err := pool.Do(ctx, func(conn *ThriftClientConn) error {
+			used = conn
+			_, err := conn.Client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1})
+			return err
+		})
+		return used, err
+	}
+
+	t.Run("closed", func(t *testing.T) {
+		proxy, addr := startProxy(t, upstream)
+		pool := NewThriftConnectionPool(addr, 2, 2, time.Minute)
+		defer pool.Close(ctx)
+		first, err := echo(pool)
+		if err != nil {
+			t.Fatalf("调用失败: %v", err)
+		}
+		// 服务端关闭连接后借出时发现 EOF，不需要开启探测
+		proxy.closeAll()
+		time.Sleep(50 * time.Millisecond)
+		if used, err := echo(pool); err != nil || used == first {
+			t.Fatalf("期望服务端关闭后使用新连接, 实际: %v, 同一连接 %v", err, used == first)
+		}
+	})
+
+	t.Run("half-open", func(t *testing.T) {
+		proxy, addr := startProxy(t, upstream)
+		pool := NewThriftConnectionPool(addr, 2, 2, time.Minute, WithProbe(50*time.Millisecond, 100*time.Millisecond))
+		defer pool.Close(ctx)
+		first, err := echo(pool)
+		if err != nil {
+			t.Fatalf("调用失败: %v", err)
+		}
+		// 刚归还的连接不探测
+		if used, err := echo(pool); err != nil || used != first {
+			t.Fatalf("期望复用连接, 实际: %v, 同一连接 %v", err, used == first)
+		}
+
+		// 半开的连接仍然可以写入，只有探测超时才能发现
+		proxy.freeze()
+		time.Sleep(80 * time.Millisecond)
+		start := time.Now()
+		used, err := echo(pool)
+		if err != nil || used == first {
+			t.Fatalf("期望探测后使用新连接, 实际: %v, 同一连接 %v", err, used == first)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("探测没有按超时时间返回: %v", d)
+		}
+	})
+}
+
+// TestPoolProbeClearsHeaders 测试探测不会带上前一次调用留在 THeader 协议上的请求头
+func TestPoolProbeClearsHeaders(t *testing.T) {
+	// 驱逐检测同样会探测，记录每次探测带上的幂等键
+	var (
+		mu     sync.Mutex
+		probed []string
+	)
+	addr := startServer(t, &conf.Server_Thrift{Transport: thriftx.TransportHeader}, server.ThriftMiddleware(
+		func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				if tr, ok := transport.FromServerContext(ctx); ok && tr.Operation() == "Health.Check" {
+					mu.Lock()
+					probed = append(probed, tr.RequestHeader().Get(thriftx.IdempotencyKeyHeader))
+					mu.Unlock()
+				}
+				return handler(ctx, req)
+			}
+		},
+	))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	pool := NewThriftConnectionPool(addr, 1, 1, time.Minute,
+		WithTransport(thriftx.TransportHeader), WithProbe(20*time.Millisecond, time.Second))
+	defer pool.Close(ctx)
+
+	echo := func(ctx context.Context) error {
+		return pool.Do(ctx, func(conn *ThriftClientConn) error {
+			_, err := conn.Client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1})
+			return err
+		})
+	}
+	if err := echo(WithIdempotencyKey(ctx, "key-1")); err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+	// 空闲超过探测间隔后再次借出，先探测连接
+	time.Sleep(50 * time.Millisecond)
+	if err := echo(ctx); err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(probed) == 0 {
+		t.Fatal("没有发起探测")
+	}
+	for _, key := range probed {
+		if key != "" {
+			t.Fatalf("探测带上了前一次调用的幂等键 %q", key)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thriftx"
+)
+
+// testCerts 测试时生成的自签名证书文件
+type testCerts struct {
+	caFile, serverCert, serverKey, clientCert, clientKey string
+}
+
+// generateTestCerts 生成 CA 以及由其签发的服务端、客户端证书
+func generateTestCerts(t *testing.T) *testCerts {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("生成 CA 私钥失败: %v", err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("生成 CA 证书失败: %v", err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatalf("解析 CA 证书失败: %v", err)
+	}
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("生成私钥失败: %v", err)
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatalf("签发证书失败: %v", err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatalf("编码私钥失败: %v", err)
+		}
+		certFile := writePEM(t, dir, name+".crt", "CERTIFICATE", der)
+		keyFile := writePEM(t, dir, name+".key", "EC PRIVATE KEY", keyDER)
+		return certFile, keyFile
+	}
+
+	certs := &testCerts{caFile: writePEM(t, dir, "ca.crt", "CERTIFICATE", caDER)}
+	certs.serverCert, certs.serverKey = issue(2, "server", x509.ExtKeyUsageServerAuth)
+	certs.clientCert, certs.clientKey = issue(3, "client", x509.ExtKeyUsageClientAuth)
+	return certs
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("写入 %s 失败: %v", name, err)
+	}
+	return path
+}
+
+// echoWithPool 从连接池取连接调用一次 EchoData
+func echoWithPool(pool *ThriftConnectionPool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := pool.GetConnection(ctx)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Client.EchoData(ctx, []byte("tls"), &user_service.User{ID: 1}); err != nil {
+		pool.CloseConnection(ctx, conn)
+		return err
+	}
+	return pool.ReleaseConnection(ctx, conn)
+}
+
+// TestThriftTLS 测试服务端启用 TLS 后连接池通过 TLS 拨号调用
+func TestThriftTLS(t *testing.T) {
+	certs := generateTestCerts(t)
+	addr := startServer(t, &conf.Server_Thrift{
+		Tls: &conf.Server_Thrift_TLS{CertFile: certs.serverCert, KeyFile: certs.serverKey},
+	})
+
+	tlsConfig, err := thriftx.NewClientTLSConfig(certs.caFile, "", "", "")
+	if err != nil {
+		t.Fatalf("创建客户端 TLS 配置失败: %v", err)
+	}
+	pool := NewThriftConnectionPool(addr, 1, 1, time.Minute, WithTLSConfig(tlsConfig))
+	defer pool.Close(context.Background())
+	if err := echoWithPool(pool); err != nil {
+		t.Fatalf("TLS 调用失败: %v", err)
+	}
+
+	// 明文客户端无法与 TLS 服务端通信
+	plain := NewThriftConnectionPool(addr, 1, 1, time.Minute)
+	defer plain.Close(context.Background())
+	if err := echoWithPool(plain); err == nil {
+		t.Fatal("期望明文客户端调用失败")
+	}
+}
+
+// TestThriftMutualTLS 测试服务端要求并校验客户端证书
+func TestThriftMutualTLS(t *testing.T) {
+	certs := generateTestCerts(t)
+	addr := startServer(t, &conf.Server_Thrift{
+		Tls: &conf.Server_Thrift_TLS{
+			CertFile:   certs.serverCert,
+			KeyFile:    certs.serverKey,
+			CaFile:     certs.caFile,
+			ClientAuth: "require_and_verify",
+		},
+	})
+
+	tlsConfig, err := thriftx.NewClientTLSConfig(certs.caFile, certs.clientCert, certs.clientKey, "")
+	if err != nil {
+		t.Fatalf("创建客户端 TLS 配置失败: %v", err)
+	}
+	pool := NewThriftConnectionPool(addr, 1, 1, time.Minute, WithTLSConfig(tlsConfig))
+	defer pool.Close(context.Background())
+	if err := echoWithPool(pool); err != nil {
+		t.Fatalf("mTLS 调用失败: %v", err)
+	}
+
+	// 未提供客户端证书时握手失败
+	noCertConfig, err := thriftx.NewClientTLSConfig(certs.caFile, "", "", "")
+	if err != nil {
+		t.Fatalf("创建客户端 TLS 配置失败: %v", err)
+	}
+	noCert := NewThriftConnectionPool(addr, 1, 1, time.Minute, WithTLSConfig(noCertConfig))
+	defer noCert.Close(context.Background())
+	if err := echoWithPool(noCert); err == nil {
+		t.Fatal("期望未提供客户端证书时调用失败")
+	}
+}
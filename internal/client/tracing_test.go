@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thriftx"
+	"aboveThriftRPC/internal/server"
+
+	"github.com/go-kratos/kratos/v2/middleware/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestThriftTracing 测试客户端 span 通过 THeader 传递 W3C trace context，服务端 span 是客户端 span 的子 span
+func TestThriftTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	addr := startServer(t, &conf.Server_Thrift{Transport: thriftx.TransportHeader}, server.ThriftMiddleware(tracing.Server()))
+	pool := NewThriftConnectionPool(addr, 1, 1, time.Minute, WithTransport(thriftx.TransportHeader))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer pool.Close(ctx)
+
+	ctx, root := otel.Tracer("test").Start(ctx, "root")
+	conn, err := pool.GetConnection(ctx)
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	if _, err := conn.Client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1}); err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	pool.ReleaseConnection(ctx, conn)
+	root.End()
+
+	// 服务端 span 在写回响应后结束，等待导出
+	var spans tracetest.SpanStubs
+	for i := 0; i < 50; i++ {
+		if spans = exporter.GetSpans(); len(spans) == 3 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	byKind := map[trace.SpanKind]tracetest.SpanStub{}
+	for _, span := range spans {
+		byKind[span.SpanKind] = span
+	}
+	client, ok := byKind[trace.SpanKindClient]
+	if !ok || client.Name != "UserService.echoData" || client.Parent.SpanID() != root.SpanContext().SpanID() {
+		t.Fatalf("客户端 span 错误: %+v", client)
+	}
+	srv, ok := byKind[trace.SpanKindServer]
+	if !ok || srv.Name != "UserService.echoData" {
+		t.Fatalf("服务端 span 错误: %+v", srv)
+	}
+	if !srv.Parent.IsRemote() || srv.Parent.SpanID() != client.SpanContext.SpanID() || srv.SpanContext.TraceID() != root.SpanContext().TraceID() {
+		t.Fatalf("服务端 span 的父 span 为 %v, 期望客户端 span %v", srv.Parent, client.SpanContext)
+	}
+}
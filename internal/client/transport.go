@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+var _ transport.Transporter = (*Transport)(nil)
+
+// Transport thrift 客户端 transport，请求头与响应头基于 THeader
+type Transport struct {
+	endpoint    string
+	operation   string
+	reqHeader   thriftx.HeaderCarrier
+	replyHeader thriftx.HeaderCarrier
+}
+
+// Kind returns the transport kind.
+func (tr *Transport) Kind() transport.Kind {
+	return thriftx.KindThrift
+}
+
+// Endpoint returns the transport endpoint.
+func (tr *Transport) Endpoint() string {
+	return tr.endpoint
+}
+
+// Operation returns the transport operation, e.g. GiftService.SendGift.
+func (tr *Transport) Operation() string {
+	return tr.operation
+}
+
+// RequestHeader returns the request header.
+func (tr *Transport) RequestHeader() transport.Header {
+	return tr.reqHeader
+}
+
+// ReplyHeader returns the reply header.
+func (tr *Transport) ReplyHeader() transport.Header {
+	return tr.replyHeader
+}
+
+// transportMiddleware 在 context 中注入客户端 transport 并执行 kratos 中间件
+//
+// 多路协议包裹后 TStandardClient 无法识别 THeader 协议，所以请求头和响应头直接通过 hp 读写，
+// hp 为空（非 THeader 协议）时头信息只在本地可见。
+func transportMiddleware(endpoint, service string, hp *thrift.THeaderProtocol, m middleware.Middleware) thrift.ClientMiddleware {
+	return func(next thrift.TClient) thrift.TClient {
+		return thrift.WrappedTClient{
+			Wrapped: func(ctx context.Context, method string, args, result thrift.TStruct) (thrift.ResponseMeta, error) {
+				tr := &Transport{
+					endpoint:    endpoint,
+					operation:   thriftx.Operation(service, method),
+					reqHeader:   make(thriftx.HeaderCarrier),
+					replyHeader: make(thriftx.HeaderCarrier),
+				}
+				// 保留通过 thrift.SetHeader 设置到 context 中的请求头
+				for _, key := range thrift.GetWriteHeaderList(ctx) {
+					if value, ok := thrift.GetHeader(ctx, key); ok {
+						tr.reqHeader[key] = value
+					}
+				}
+				var meta thrift.ResponseMeta
+				h := m(func(ctx context.Context, req any) (any, error) {
+					if hp != nil {
+						hp.ClearWriteHeaders()
+						for key, value := range tr.reqHeader {
+							hp.SetWriteHeader(key, value)
+						}
+					}
+					var err error
+					meta, err = next.Call(ctx, method, args, result)
+					if hp != nil {
+						meta.Headers = hp.GetReadHeaders()
+						for key, value := range meta.Headers {
+							tr.replyHeader[key] = value
+						}
+					}
+					return result, err
+				})
+				_, err := h(transport.NewClientContext(ctx, tr), args)
+				return meta, err
+			},
+		}
+	}
+}
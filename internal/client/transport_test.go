@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thriftx"
+	"aboveThriftRPC/internal/server"
+
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// TestThriftTransporter 测试服务端与客户端中间件都能取到 thrift transport，并通过 THeader 传递头信息
+func TestThriftTransporter(t *testing.T) {
+	serverSeen := make(chan transport.Transporter, 1)
+	addr := startServer(t, &conf.Server_Thrift{Transport: thriftx.TransportHeader}, server.ThriftMiddleware(
+		func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				tr, ok := transport.FromServerContext(ctx)
+				if !ok {
+					t.Error("服务端 context 中没有 transport")
+					return handler(ctx, req)
+				}
+				tr.ReplyHeader().Set("x-reply-user", tr.RequestHeader().Get("x-md-user"))
+				serverSeen <- tr
+				return handler(ctx, req)
+			}
+		},
+	))
+
+	var clientSeen transport.Transporter
+	pool := NewThriftConnectionPool(addr, 1, 1, time.Minute,
+		WithTransport(thriftx.TransportHeader),
+		WithMiddleware(func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				tr, ok := transport.FromClientContext(ctx)
+				if !ok {
+					t.Error("客户端 context 中没有 transport")
+					return handler(ctx, req)
+				}
+				tr.RequestHeader().Set("x-md-user", "alice")
+				clientSeen = tr
+				return handler(ctx, req)
+			}
+		}),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer pool.Close(ctx)
+
+	conn, err := pool.GetConnection(ctx)
+	if err != nil {
+		t.Fatalf("获取连接失败: %v", err)
+	}
+	if _, err := conn.Client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1}); err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	pool.ReleaseConnection(ctx, conn)
+
+	tr := <-serverSeen
+	if tr.Kind() != thriftx.KindThrift || tr.Operation() != "UserService.echoData" {
+		t.Fatalf("服务端 transport 不符合预期: kind=%s operation=%s", tr.Kind(), tr.Operation())
+	}
+	if tr.Endpoint() != "thrift://"+addr {
+		t.Fatalf("服务端 endpoint 不符合预期: %s", tr.Endpoint())
+	}
+	if clientSeen.Kind() != thriftx.KindThrift || clientSeen.Operation() != "UserService.echoData" {
+		t.Fatalf("客户端 transport 不符合预期: kind=%s operation=%s", clientSeen.Kind(), clientSeen.Operation())
+	}
+	if got := clientSeen.ReplyHeader().Get("x-reply-user"); got != "alice" {
+		t.Fatalf("客户端未收到响应头, x-reply-user=%q", got)
+	}
+}
+
+// TestWithMiddlewareAppend 测试多次 WithMiddleware 追加中间件，按添加顺序由外到内执行
+func TestWithMiddlewareAppend(t *testing.T) {
+	addr := startServer(t, &conf.Server_Thrift{})
+	var calls []string
+	record := func(name string) middleware.Middleware {
+		return func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				calls = append(calls, name)
+				return handler(ctx, req)
+			}
+		}
+	}
+	pool := NewThriftConnectionPool(addr, 1, 1, time.Minute, WithMiddleware(record("first")), WithMiddleware(record("second"), record("third")))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer pool.Close(ctx)
+
+	err := pool.Do(ctx, func(conn *Conn[user_service.UserServiceClient]) error {
+		_, err := conn.Client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(calls, want) {
+		t.Fatalf("中间件执行顺序为 %v, 期望 %v", calls, want)
+	}
+}
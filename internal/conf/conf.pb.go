@@ -26,6 +26,7 @@ type Bootstrap struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Server        *Server                `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
 	Data          *Data                  `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Registry      *Registry              `protobuf:"bytes,3,opt,name=registry,proto3" json:"registry,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,6 +75,13 @@ func (x *Bootstrap) GetData() *Data {
 	return nil
 }
 
+func (x *Bootstrap) GetRegistry() *Registry {
+	if x != nil {
+		return x.Registry
+	}
+	return nil
+}
+
 type Server struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Http          *Server_HTTP           `protobuf:"bytes,1,opt,name=http,proto3" json:"http,omitempty"`
@@ -186,18 +194,73 @@ func (x *Data) GetRedis() *Data_Redis {
 	return nil
 }
 
+type Registry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 注册中心类型：file，留空时不注册
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// file 注册中心保存服务实例的文件路径
+	Path          string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Registry) Reset() {
+	*x = Registry{}
+	mi := &file_conf_conf_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Registry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Registry) ProtoMessage() {}
+
+func (x *Registry) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Registry.ProtoReflect.Descriptor instead.
+func (*Registry) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Registry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Registry) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
 type Server_HTTP struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
 	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
 	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	ThriftPath    string                 `protobuf:"bytes,4,opt,name=thrift_path,json=thriftPath,proto3" json:"thrift_path,omitempty"` // thrift over http 的路径，默认 /thrift
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Server_HTTP) Reset() {
 	*x = Server_HTTP{}
-	mi := &file_conf_conf_proto_msgTypes[3]
+	mi := &file_conf_conf_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -209,7 +272,7 @@ func (x *Server_HTTP) String() string {
 func (*Server_HTTP) ProtoMessage() {}
 
 func (x *Server_HTTP) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[3]
+	mi := &file_conf_conf_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -246,6 +309,13 @@ func (x *Server_HTTP) GetTimeout() *durationpb.Duration {
 	return nil
 }
 
+func (x *Server_HTTP) GetThriftPath() string {
+	if x != nil {
+		return x.ThriftPath
+	}
+	return ""
+}
+
 type Server_GRPC struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
@@ -257,7 +327,7 @@ type Server_GRPC struct {
 
 func (x *Server_GRPC) Reset() {
 	*x = Server_GRPC{}
-	mi := &file_conf_conf_proto_msgTypes[4]
+	mi := &file_conf_conf_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -269,7 +339,7 @@ func (x *Server_GRPC) String() string {
 func (*Server_GRPC) ProtoMessage() {}
 
 func (x *Server_GRPC) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[4]
+	mi := &file_conf_conf_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -307,17 +377,46 @@ func (x *Server_GRPC) GetTimeout() *durationpb.Duration {
 }
 
 type Server_Thrift struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Network       string                 `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
-	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
-	Timeout       *durationpb.Duration   `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tcp（默认）、tcp4、tcp6、unix，unix 时 addr 为 socket 文件路径
+	Network string               `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Addr    string               `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	Timeout *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// binary（默认）、compact、json、header、auto
+	Protocol string `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	// buffered（默认）、framed、header，客户端连接池使用相同取值
+	Transport string `protobuf:"bytes,5,opt,name=transport,proto3" json:"transport,omitempty"`
+	// 配置 cert_file 与 key_file 后启用 TLS
+	Tls *Server_Thrift_TLS `protobuf:"bytes,6,opt,name=tls,proto3" json:"tls,omitempty"`
+	// 最大并发连接数，0 表示不限制
+	MaxConnections int32 `protobuf:"varint,7,opt,name=max_connections,json=maxConnections,proto3" json:"max_connections,omitempty"`
+	// 最大同时处理的请求数，0 表示不限制
+	MaxInFlight int32 `protobuf:"varint,8,opt,name=max_in_flight,json=maxInFlight,proto3" json:"max_in_flight,omitempty"`
+	// 连接数达到上限后等待 worker 的连接队列长度
+	AcceptBacklog int32 `protobuf:"varint,9,opt,name=accept_backlog,json=acceptBacklog,proto3" json:"accept_backlog,omitempty"`
+	// 超出上限时的策略：reject（默认）直接拒绝，queue 排队等待
+	OverloadPolicy string `protobuf:"bytes,10,opt,name=overload_policy,json=overloadPolicy,proto3" json:"overload_policy,omitempty"`
+	// queue 策略下请求等待处理的最长时间，0 表示一直等待
+	QueueTimeout *durationpb.Duration `protobuf:"bytes,11,opt,name=queue_timeout,json=queueTimeout,proto3" json:"queue_timeout,omitempty"`
+	// 按方法覆盖 timeout，键为 Service.method，如 GiftService.GetTop10Senders
+	MethodTimeouts map[string]*durationpb.Duration `protobuf:"bytes,12,rep,name=method_timeouts,json=methodTimeouts,proto3" json:"method_timeouts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// network 为 unix 时 socket 文件的权限，八进制字符串，如 "0660"，默认受 umask 影响
+	UnixSocketMode string `protobuf:"bytes,13,opt,name=unix_socket_mode,json=unixSocketMode,proto3" json:"unix_socket_mode,omitempty"`
+	// 单条消息与单个字段的最大字节数，默认 16MB
+	MaxMessageSize int32 `protobuf:"varint,14,opt,name=max_message_size,json=maxMessageSize,proto3" json:"max_message_size,omitempty"`
+	// framed、header 传输层单帧的最大字节数，默认 16MB
+	MaxFrameSize int32 `protobuf:"varint,15,opt,name=max_frame_size,json=maxFrameSize,proto3" json:"max_frame_size,omitempty"`
+	// 按方法限制请求的字节数，键为 Service.method，如 GiftService.SendGift，超出时返回 REQUEST_TOO_LARGE
+	MethodMaxRequestSizes map[string]int32 `protobuf:"bytes,16,rep,name=method_max_request_sizes,json=methodMaxRequestSizes,proto3" json:"method_max_request_sizes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// 访问日志
+	AccessLog     *Server_Thrift_AccessLog `protobuf:"bytes,17,opt,name=access_log,json=accessLog,proto3" json:"access_log,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Server_Thrift) Reset() {
 	*x = Server_Thrift{}
-	mi := &file_conf_conf_proto_msgTypes[5]
+	mi := &file_conf_conf_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -329,7 +428,7 @@ func (x *Server_Thrift) String() string {
 func (*Server_Thrift) ProtoMessage() {}
 
 func (x *Server_Thrift) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[5]
+	mi := &file_conf_conf_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,6 +465,256 @@ func (x *Server_Thrift) GetTimeout() *durationpb.Duration {
 	return nil
 }
 
+func (x *Server_Thrift) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *Server_Thrift) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *Server_Thrift) GetTls() *Server_Thrift_TLS {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
+func (x *Server_Thrift) GetMaxConnections() int32 {
+	if x != nil {
+		return x.MaxConnections
+	}
+	return 0
+}
+
+func (x *Server_Thrift) GetMaxInFlight() int32 {
+	if x != nil {
+		return x.MaxInFlight
+	}
+	return 0
+}
+
+func (x *Server_Thrift) GetAcceptBacklog() int32 {
+	if x != nil {
+		return x.AcceptBacklog
+	}
+	return 0
+}
+
+func (x *Server_Thrift) GetOverloadPolicy() string {
+	if x != nil {
+		return x.OverloadPolicy
+	}
+	return ""
+}
+
+func (x *Server_Thrift) GetQueueTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.QueueTimeout
+	}
+	return nil
+}
+
+func (x *Server_Thrift) GetMethodTimeouts() map[string]*durationpb.Duration {
+	if x != nil {
+		return x.MethodTimeouts
+	}
+	return nil
+}
+
+func (x *Server_Thrift) GetUnixSocketMode() string {
+	if x != nil {
+		return x.UnixSocketMode
+	}
+	return ""
+}
+
+func (x *Server_Thrift) GetMaxMessageSize() int32 {
+	if x != nil {
+		return x.MaxMessageSize
+	}
+	return 0
+}
+
+func (x *Server_Thrift) GetMaxFrameSize() int32 {
+	if x != nil {
+		return x.MaxFrameSize
+	}
+	return 0
+}
+
+func (x *Server_Thrift) GetMethodMaxRequestSizes() map[string]int32 {
+	if x != nil {
+		return x.MethodMaxRequestSizes
+	}
+	return nil
+}
+
+func (x *Server_Thrift) GetAccessLog() *Server_Thrift_AccessLog {
+	if x != nil {
+		return x.AccessLog
+	}
+	return nil
+}
+
+type Server_Thrift_TLS struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	CertFile string                 `protobuf:"bytes,1,opt,name=cert_file,json=certFile,proto3" json:"cert_file,omitempty"`
+	KeyFile  string                 `protobuf:"bytes,2,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	// 用于校验客户端证书的 CA
+	CaFile string `protobuf:"bytes,3,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	// none、request、require、verify_if_given、require_and_verify，配置了 ca_file 时默认 require_and_verify
+	ClientAuth    string `protobuf:"bytes,4,opt,name=client_auth,json=clientAuth,proto3" json:"client_auth,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_Thrift_TLS) Reset() {
+	*x = Server_Thrift_TLS{}
+	mi := &file_conf_conf_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Thrift_TLS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Thrift_TLS) ProtoMessage() {}
+
+func (x *Server_Thrift_TLS) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Thrift_TLS.ProtoReflect.Descriptor instead.
+func (*Server_Thrift_TLS) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{1, 2, 0}
+}
+
+func (x *Server_Thrift_TLS) GetCertFile() string {
+	if x != nil {
+		return x.CertFile
+	}
+	return ""
+}
+
+func (x *Server_Thrift_TLS) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *Server_Thrift_TLS) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *Server_Thrift_TLS) GetClientAuth() string {
+	if x != nil {
+		return x.ClientAuth
+	}
+	return ""
+}
+
+type Server_Thrift_AccessLog struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 是否输出访问日志
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// 成功请求的采样比例，取值 0~1，0 表示全部记录；失败与慢请求总是记录
+	SampleRate float64 `protobuf:"fixed64,2,opt,name=sample_rate,json=sampleRate,proto3" json:"sample_rate,omitempty"`
+	// 耗时超过该值的请求总是记录，0 表示不区分
+	SlowThreshold *durationpb.Duration `protobuf:"bytes,3,opt,name=slow_threshold,json=slowThreshold,proto3" json:"slow_threshold,omitempty"`
+	// 是否记录脱敏后的请求参数与响应结果
+	LogPayload bool `protobuf:"varint,4,opt,name=log_payload,json=logPayload,proto3" json:"log_payload,omitempty"`
+	// 需要脱敏的字段，格式为 Struct.field，方法参数为 Service.method.arg，默认为 User.email、UserDetails.phone，
+	// binary 字段总是只记录长度
+	RedactFields  []string `protobuf:"bytes,5,rep,name=redact_fields,json=redactFields,proto3" json:"redact_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Server_Thrift_AccessLog) Reset() {
+	*x = Server_Thrift_AccessLog{}
+	mi := &file_conf_conf_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Server_Thrift_AccessLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Server_Thrift_AccessLog) ProtoMessage() {}
+
+func (x *Server_Thrift_AccessLog) ProtoReflect() protoreflect.Message {
+	mi := &file_conf_conf_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Server_Thrift_AccessLog.ProtoReflect.Descriptor instead.
+func (*Server_Thrift_AccessLog) Descriptor() ([]byte, []int) {
+	return file_conf_conf_proto_rawDescGZIP(), []int{1, 2, 1}
+}
+
+func (x *Server_Thrift_AccessLog) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *Server_Thrift_AccessLog) GetSampleRate() float64 {
+	if x != nil {
+		return x.SampleRate
+	}
+	return 0
+}
+
+func (x *Server_Thrift_AccessLog) GetSlowThreshold() *durationpb.Duration {
+	if x != nil {
+		return x.SlowThreshold
+	}
+	return nil
+}
+
+func (x *Server_Thrift_AccessLog) GetLogPayload() bool {
+	if x != nil {
+		return x.LogPayload
+	}
+	return false
+}
+
+func (x *Server_Thrift_AccessLog) GetRedactFields() []string {
+	if x != nil {
+		return x.RedactFields
+	}
+	return nil
+}
+
 type Data_Database struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Driver        string                 `protobuf:"bytes,1,opt,name=driver,proto3" json:"driver,omitempty"`
@@ -376,7 +725,7 @@ type Data_Database struct {
 
 func (x *Data_Database) Reset() {
 	*x = Data_Database{}
-	mi := &file_conf_conf_proto_msgTypes[6]
+	mi := &file_conf_conf_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -388,7 +737,7 @@ func (x *Data_Database) String() string {
 func (*Data_Database) ProtoMessage() {}
 
 func (x *Data_Database) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[6]
+	mi := &file_conf_conf_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -430,7 +779,7 @@ type Data_Redis struct {
 
 func (x *Data_Redis) Reset() {
 	*x = Data_Redis{}
-	mi := &file_conf_conf_proto_msgTypes[7]
+	mi := &file_conf_conf_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -442,7 +791,7 @@ func (x *Data_Redis) String() string {
 func (*Data_Redis) ProtoMessage() {}
 
 func (x *Data_Redis) ProtoReflect() protoreflect.Message {
-	mi := &file_conf_conf_proto_msgTypes[7]
+	mi := &file_conf_conf_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -491,26 +840,66 @@ var File_conf_conf_proto protoreflect.FileDescriptor
 const file_conf_conf_proto_rawDesc = "" +
 	"\n" +
 	"\x0fconf/conf.proto\x12\n" +
-	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"]\n" +
+	"kratos.api\x1a\x1egoogle/protobuf/duration.proto\"\x8f\x01\n" +
 	"\tBootstrap\x12*\n" +
 	"\x06server\x18\x01 \x01(\v2\x12.kratos.api.ServerR\x06server\x12$\n" +
-	"\x04data\x18\x02 \x01(\v2\x10.kratos.api.DataR\x04data\"\xd8\x03\n" +
+	"\x04data\x18\x02 \x01(\v2\x10.kratos.api.DataR\x04data\x120\n" +
+	"\bregistry\x18\x03 \x01(\v2\x14.kratos.api.RegistryR\bregistry\"\xba\r\n" +
 	"\x06Server\x12+\n" +
 	"\x04http\x18\x01 \x01(\v2\x17.kratos.api.Server.HTTPR\x04http\x12+\n" +
 	"\x04grpc\x18\x02 \x01(\v2\x17.kratos.api.Server.GRPCR\x04grpc\x121\n" +
-	"\x06thrift\x18\x03 \x01(\v2\x19.kratos.api.Server.ThriftR\x06thrift\x1ai\n" +
+	"\x06thrift\x18\x03 \x01(\v2\x19.kratos.api.Server.ThriftR\x06thrift\x1a\x8a\x01\n" +
 	"\x04HTTP\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1ai\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1f\n" +
+	"\vthrift_path\x18\x04 \x01(\tR\n" +
+	"thriftPath\x1ai\n" +
 	"\x04GRPC\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1ak\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x1a\xaa\n" +
+	"\n" +
 	"\x06Thrift\x12\x18\n" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x123\n" +
-	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"\xdd\x02\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\x12\x1a\n" +
+	"\bprotocol\x18\x04 \x01(\tR\bprotocol\x12\x1c\n" +
+	"\ttransport\x18\x05 \x01(\tR\ttransport\x12/\n" +
+	"\x03tls\x18\x06 \x01(\v2\x1d.kratos.api.Server.Thrift.TLSR\x03tls\x12'\n" +
+	"\x0fmax_connections\x18\a \x01(\x05R\x0emaxConnections\x12\"\n" +
+	"\rmax_in_flight\x18\b \x01(\x05R\vmaxInFlight\x12%\n" +
+	"\x0eaccept_backlog\x18\t \x01(\x05R\racceptBacklog\x12'\n" +
+	"\x0foverload_policy\x18\n" +
+	" \x01(\tR\x0eoverloadPolicy\x12>\n" +
+	"\rqueue_timeout\x18\v \x01(\v2\x19.google.protobuf.DurationR\fqueueTimeout\x12V\n" +
+	"\x0fmethod_timeouts\x18\f \x03(\v2-.kratos.api.Server.Thrift.MethodTimeoutsEntryR\x0emethodTimeouts\x12(\n" +
+	"\x10unix_socket_mode\x18\r \x01(\tR\x0eunixSocketMode\x12(\n" +
+	"\x10max_message_size\x18\x0e \x01(\x05R\x0emaxMessageSize\x12$\n" +
+	"\x0emax_frame_size\x18\x0f \x01(\x05R\fmaxFrameSize\x12m\n" +
+	"\x18method_max_request_sizes\x18\x10 \x03(\v24.kratos.api.Server.Thrift.MethodMaxRequestSizesEntryR\x15methodMaxRequestSizes\x12B\n" +
+	"\n" +
+	"access_log\x18\x11 \x01(\v2#.kratos.api.Server.Thrift.AccessLogR\taccessLog\x1aw\n" +
+	"\x03TLS\x12\x1b\n" +
+	"\tcert_file\x18\x01 \x01(\tR\bcertFile\x12\x19\n" +
+	"\bkey_file\x18\x02 \x01(\tR\akeyFile\x12\x17\n" +
+	"\aca_file\x18\x03 \x01(\tR\x06caFile\x12\x1f\n" +
+	"\vclient_auth\x18\x04 \x01(\tR\n" +
+	"clientAuth\x1a\xce\x01\n" +
+	"\tAccessLog\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12\x1f\n" +
+	"\vsample_rate\x18\x02 \x01(\x01R\n" +
+	"sampleRate\x12@\n" +
+	"\x0eslow_threshold\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\rslowThreshold\x12\x1f\n" +
+	"\vlog_payload\x18\x04 \x01(\bR\n" +
+	"logPayload\x12#\n" +
+	"\rredact_fields\x18\x05 \x03(\tR\fredactFields\x1a\\\n" +
+	"\x13MethodTimeoutsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12/\n" +
+	"\x05value\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x05value:\x028\x01\x1aH\n" +
+	"\x1aMethodMaxRequestSizesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"\xdd\x02\n" +
 	"\x04Data\x125\n" +
 	"\bdatabase\x18\x01 \x01(\v2\x19.kratos.api.Data.DatabaseR\bdatabase\x12,\n" +
 	"\x05redis\x18\x02 \x01(\v2\x16.kratos.api.Data.RedisR\x05redis\x1a:\n" +
@@ -521,7 +910,10 @@ const file_conf_conf_proto_rawDesc = "" +
 	"\anetwork\x18\x01 \x01(\tR\anetwork\x12\x12\n" +
 	"\x04addr\x18\x02 \x01(\tR\x04addr\x12<\n" +
 	"\fread_timeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\vreadTimeout\x12>\n" +
-	"\rwrite_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeoutB Z\x1eaboveKratos/internal/conf;confb\x06proto3"
+	"\rwrite_timeout\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\fwriteTimeout\"2\n" +
+	"\bRegistry\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04path\x18\x02 \x01(\tR\x04pathB Z\x1eaboveKratos/internal/conf;confb\x06proto3"
 
 var (
 	file_conf_conf_proto_rawDescOnce sync.Once
@@ -535,36 +927,49 @@ func file_conf_conf_proto_rawDescGZIP() []byte {
 	return file_conf_conf_proto_rawDescData
 }
 
-var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_conf_conf_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
 var file_conf_conf_proto_goTypes = []any{
-	(*Bootstrap)(nil),           // 0: kratos.api.Bootstrap
-	(*Server)(nil),              // 1: kratos.api.Server
-	(*Data)(nil),                // 2: kratos.api.Data
-	(*Server_HTTP)(nil),         // 3: kratos.api.Server.HTTP
-	(*Server_GRPC)(nil),         // 4: kratos.api.Server.GRPC
-	(*Server_Thrift)(nil),       // 5: kratos.api.Server.Thrift
-	(*Data_Database)(nil),       // 6: kratos.api.Data.Database
-	(*Data_Redis)(nil),          // 7: kratos.api.Data.Redis
-	(*durationpb.Duration)(nil), // 8: google.protobuf.Duration
+	(*Bootstrap)(nil),               // 0: kratos.api.Bootstrap
+	(*Server)(nil),                  // 1: kratos.api.Server
+	(*Data)(nil),                    // 2: kratos.api.Data
+	(*Registry)(nil),                // 3: kratos.api.Registry
+	(*Server_HTTP)(nil),             // 4: kratos.api.Server.HTTP
+	(*Server_GRPC)(nil),             // 5: kratos.api.Server.GRPC
+	(*Server_Thrift)(nil),           // 6: kratos.api.Server.Thrift
+	(*Server_Thrift_TLS)(nil),       // 7: kratos.api.Server.Thrift.TLS
+	(*Server_Thrift_AccessLog)(nil), // 8: kratos.api.Server.Thrift.AccessLog
+	nil,                             // 9: kratos.api.Server.Thrift.MethodTimeoutsEntry
+	nil,                             // 10: kratos.api.Server.Thrift.MethodMaxRequestSizesEntry
+	(*Data_Database)(nil),           // 11: kratos.api.Data.Database
+	(*Data_Redis)(nil),              // 12: kratos.api.Data.Redis
+	(*durationpb.Duration)(nil),     // 13: google.protobuf.Duration
 }
 var file_conf_conf_proto_depIdxs = []int32{
 	1,  // 0: kratos.api.Bootstrap.server:type_name -> kratos.api.Server
 	2,  // 1: kratos.api.Bootstrap.data:type_name -> kratos.api.Data
-	3,  // 2: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
-	4,  // 3: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
-	5,  // 4: kratos.api.Server.thrift:type_name -> kratos.api.Server.Thrift
-	6,  // 5: kratos.api.Data.database:type_name -> kratos.api.Data.Database
-	7,  // 6: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
-	8,  // 7: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
-	8,  // 8: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
-	8,  // 9: kratos.api.Server.Thrift.timeout:type_name -> google.protobuf.Duration
-	8,  // 10: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
-	8,  // 11: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
-	12, // [12:12] is the sub-list for method output_type
-	12, // [12:12] is the sub-list for method input_type
-	12, // [12:12] is the sub-list for extension type_name
-	12, // [12:12] is the sub-list for extension extendee
-	0,  // [0:12] is the sub-list for field type_name
+	3,  // 2: kratos.api.Bootstrap.registry:type_name -> kratos.api.Registry
+	4,  // 3: kratos.api.Server.http:type_name -> kratos.api.Server.HTTP
+	5,  // 4: kratos.api.Server.grpc:type_name -> kratos.api.Server.GRPC
+	6,  // 5: kratos.api.Server.thrift:type_name -> kratos.api.Server.Thrift
+	11, // 6: kratos.api.Data.database:type_name -> kratos.api.Data.Database
+	12, // 7: kratos.api.Data.redis:type_name -> kratos.api.Data.Redis
+	13, // 8: kratos.api.Server.HTTP.timeout:type_name -> google.protobuf.Duration
+	13, // 9: kratos.api.Server.GRPC.timeout:type_name -> google.protobuf.Duration
+	13, // 10: kratos.api.Server.Thrift.timeout:type_name -> google.protobuf.Duration
+	7,  // 11: kratos.api.Server.Thrift.tls:type_name -> kratos.api.Server.Thrift.TLS
+	13, // 12: kratos.api.Server.Thrift.queue_timeout:type_name -> google.protobuf.Duration
+	9,  // 13: kratos.api.Server.Thrift.method_timeouts:type_name -> kratos.api.Server.Thrift.MethodTimeoutsEntry
+	10, // 14: kratos.api.Server.Thrift.method_max_request_sizes:type_name -> kratos.api.Server.Thrift.MethodMaxRequestSizesEntry
+	8,  // 15: kratos.api.Server.Thrift.access_log:type_name -> kratos.api.Server.Thrift.AccessLog
+	13, // 16: kratos.api.Server.Thrift.AccessLog.slow_threshold:type_name -> google.protobuf.Duration
+	13, // 17: kratos.api.Server.Thrift.MethodTimeoutsEntry.value:type_name -> google.protobuf.Duration
+	13, // 18: kratos.api.Data.Redis.read_timeout:type_name -> google.protobuf.Duration
+	13, // 19: kratos.api.Data.Redis.write_timeout:type_name -> google.protobuf.Duration
+	20, // [20:20] is the sub-list for method output_type
+	20, // [20:20] is the sub-list for method input_type
+	20, // [20:20] is the sub-list for extension type_name
+	20, // [20:20] is the sub-list for extension extendee
+	0,  // [0:20] is the sub-list for field type_name
 }
 
 func init() { file_conf_conf_proto_init() }
@@ -578,7 +983,7 @@ func file_conf_conf_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_conf_conf_proto_rawDesc), len(file_conf_conf_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   13,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -1,7 +1,10 @@
 package data
 
 import (
+	"context"
+
 	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/health"
 
 	"github.com/gomodule/redigo/redis"
 	"github.com/sirupsen/logrus"
@@ -21,8 +24,8 @@ func NewData(c *conf.Data) (*Data, func(), error) {
 	}
 
 	pool := &redis.Pool{
-		Dial: func() (redis.Conn, error) {
-			return redis.Dial("tcp", c.Redis.Addr, dialOptions...)
+		DialContext: func(ctx context.Context) (redis.Conn, error) {
+			return redis.DialContext(ctx, "tcp", c.Redis.Addr, dialOptions...)
 		},
 	}
 
@@ -32,3 +35,21 @@ func NewData(c *conf.Data) (*Data, func(), error) {
 	}
 	return &Data{redis: pool}, cleanup, nil
 }
+
+// Ping 检查 Redis 是否可用
+func (d *Data) Ping(ctx context.Context) error {
+	conn, err := d.redis.GetContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = redis.DoContext(conn, ctx, "PING")
+	return err
+}
+
+// NewHealthChecks 就绪检查依赖的数据源，接入数据库后在这里加上数据库的检查
+func NewHealthChecks(d *Data) health.Checks {
+	return health.Checks{
+		"redis": d.Ping,
+	}
+}
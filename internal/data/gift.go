@@ -25,7 +25,11 @@ func NewGiftRepo(data *Data) biz.GiftRepo {
 
 // Save saves a gift to Redis
 func (r *GiftRepo) Save(ctx context.Context, gift *biz.Gift) (*biz.Gift, error) {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return nil, err
+	}
 	defer conn.Close()
 
 	// Store gift details
@@ -73,7 +77,11 @@ func (r *GiftRepo) Save(ctx context.Context, gift *biz.Gift) (*biz.Gift, error)
 
 // QueryBySender returns gift IDs sent by a specific sender
 func (r *GiftRepo) QueryBySender(ctx context.Context, id int64) ([]int64, error) {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return nil, err
+	}
 	defer conn.Close()
 
 	senderKey := fmt.Sprintf("sender:%d:gifts", id)
@@ -90,7 +98,11 @@ func (r *GiftRepo) QueryBySender(ctx context.Context, id int64) ([]int64, error)
 
 // QueryByTime returns gift IDs sent within a time range
 func (r *GiftRepo) QueryByTime(ctx context.Context, startTime time.Time, endTime time.Time) ([]int64, error) {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return nil, err
+	}
 	defer conn.Close()
 
 	timeKey := "gifts:by_time"
@@ -110,7 +122,11 @@ func (r *GiftRepo) QueryByTime(ctx context.Context, startTime time.Time, endTime
 
 // QueryByValue returns gift IDs with value greater than or equal to the given value
 func (r *GiftRepo) QueryByValue(ctx context.Context, id int64) ([]int64, error) {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return nil, err
+	}
 	defer conn.Close()
 
 	valueKey := "gifts:by_value"
@@ -128,7 +144,11 @@ func (r *GiftRepo) QueryByValue(ctx context.Context, id int64) ([]int64, error)
 
 // GetGift retrieves a gift by ID
 func (r *GiftRepo) GetGift(ctx context.Context, id int64) (*biz.Gift, error) {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return nil, err
+	}
 	defer conn.Close()
 	giftKey := fmt.Sprintf("gift:%d", id)
 
@@ -154,7 +174,11 @@ func (r *GiftRepo) GetGift(ctx context.Context, id int64) (*biz.Gift, error) {
 
 // GetTopSenders returns top 10 senders by total gift value
 func (r *GiftRepo) GetTopSenders(ctx context.Context) ([]int64, error) {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return nil, err
+	}
 	defer conn.Close()
 
 	// This is a simplified implementation
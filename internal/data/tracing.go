@@ -0,0 +1,48 @@
+package data
+
+import (
+	"context"
+
+	"github.com/gomodule/redigo/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName data 层 span 的 instrumentation 名
+const tracerName = "aboveThriftRPC/internal/data"
+
+// conn 从连接池取出连接，连接上的每条命令都会在 ctx 的 span 下创建子 span
+//
+// 连接池已满且 ctx 到期、或者建立连接失败时返回错误，并记录到 ctx 的 span 上
+func (d *Data) conn(ctx context.Context) (redis.Conn, error) {
+	conn, err := d.redis.GetContext(ctx)
+	if err != nil {
+		span := trace.SpanFromContext(ctx)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	return &tracedConn{Conn: conn, ctx: ctx}, nil
+}
+
+// tracedConn 为每条 Redis 命令创建 client span，命令参数可能包含用户数据，不记录到 span 中
+type tracedConn struct {
+	redis.Conn
+	ctx context.Context
+}
+
+func (c *tracedConn) Do(cmd string, args ...any) (any, error) {
+	ctx, span := otel.Tracer(tracerName).Start(c.ctx, cmd,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, semconv.DBOperationKey.String(cmd)),
+	)
+	defer span.End()
+	reply, err := redis.DoContext(c.Conn, ctx, cmd, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return reply, err
+}
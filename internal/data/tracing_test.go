@@ -0,0 +1,98 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"aboveThriftRPC/internal/biz"
+
+	"github.com/gomodule/redigo/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// fakeConn 测试用的 Redis 连接，记录执行的命令
+type fakeConn struct {
+	redis.Conn
+	cmds []string
+	err  error
+}
+
+func (c *fakeConn) DoContext(ctx context.Context, cmd string, args ...any) (any, error) {
+	c.cmds = append(c.cmds, cmd)
+	return "OK", c.err
+}
+
+func (c *fakeConn) ReceiveContext(ctx context.Context) (any, error) {
+	return nil, c.err
+}
+
+// newTestTracer 使用内存 exporter 作为全局 TracerProvider，测试结束后恢复
+func newTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return exporter
+}
+
+func TestTracedConn(t *testing.T) {
+	exporter := newTestTracer(t)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GiftService.SendGift")
+
+	fake := &fakeConn{}
+	conn := &tracedConn{Conn: fake, ctx: ctx}
+	if _, err := conn.Do("SET", "gift:1", "{}"); err != nil {
+		t.Fatalf("SET 失败: %v", err)
+	}
+	fake.err = errors.New("connection refused")
+	if _, err := conn.Do("ZADD", "gifts:by_value", 10, 1); err == nil {
+		t.Fatal("期望 ZADD 失败")
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("期望 3 个 span, 实际 %d", len(spans))
+	}
+	for i, want := range []string{"SET", "ZADD"} {
+		span := spans[i]
+		if span.Name != want || span.SpanKind != trace.SpanKindClient {
+			t.Errorf("span %d 为 %s(%v), 期望 %s", i, span.Name, span.SpanKind, want)
+		}
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() || span.SpanContext.TraceID() != parent.SpanContext().TraceID() {
+			t.Errorf("%s 不是请求 span 的子 span", span.Name)
+		}
+	}
+	if spans[0].Status.Code == codes.Error || spans[1].Status.Code != codes.Error {
+		t.Errorf("span 状态为 %v, %v", spans[0].Status, spans[1].Status)
+	}
+}
+
+// TestConnError 测试取连接失败时返回错误并记录到请求 span 上
+func TestConnError(t *testing.T) {
+	exporter := newTestTracer(t)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GiftService.SendGift")
+
+	dialErr := errors.New("connection refused")
+	d := &Data{redis: &redis.Pool{
+		DialContext: func(ctx context.Context) (redis.Conn, error) { return nil, dialErr },
+	}}
+	if _, err := d.conn(ctx); !errors.Is(err, dialErr) {
+		t.Fatalf("期望返回建立连接的错误, 实际: %v", err)
+	}
+	if _, err := NewGiftRepo(d).Save(ctx, &biz.Gift{GiftID: 1}); !errors.Is(err, dialErr) {
+		t.Fatalf("期望 Save 返回建立连接的错误, 实际: %v", err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error || len(spans[0].Events) == 0 {
+		t.Fatalf("期望错误记录在请求 span 上, 实际: %+v", spans)
+	}
+}
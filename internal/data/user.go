@@ -24,7 +24,11 @@ func NewUserRepo(data *Data) biz.UserRepo {
 
 // Save saves a user to Redis
 func (r *userRepo) Save(ctx context.Context, user *biz.User) error {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return err
+	}
 	defer conn.Close()
 
 	key := fmt.Sprintf("user:%d", user.Id)
@@ -47,7 +51,11 @@ func (r *userRepo) Save(ctx context.Context, user *biz.User) error {
 
 // Get gets a user from Redis by id
 func (r *userRepo) Get(ctx context.Context, id int64) (*biz.User, error) {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return nil, err
+	}
 	defer conn.Close()
 
 	key := fmt.Sprintf("user:%d", id)
@@ -74,12 +82,16 @@ func (r *userRepo) Get(ctx context.Context, id int64) (*biz.User, error) {
 
 // Delete deletes a user from Redis by id
 func (r *userRepo) Delete(ctx context.Context, id int64) error {
-	conn := r.data.redis.Get()
+	conn, err := r.data.conn(ctx)
+	if err != nil {
+		logrus.Errorf("failed to get Redis connection: %v", err)
+		return err
+	}
 	defer conn.Close()
 
 	key := fmt.Sprintf("user:%d", id)
 
-	_, err := conn.Do("DEL", key)
+	_, err = conn.Do("DEL", key)
 	if err != nil {
 		logrus.Errorf("failed to delete user from Redis: %v", err)
 		return err
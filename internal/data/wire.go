@@ -8,4 +8,4 @@ import (
 )
 
 // ProviderSet is data providers.
-var ProviderSet = wire.NewSet(NewData, NewUserRepo, NewGiftRepo)
+var ProviderSet = wire.NewSet(NewData, NewHealthChecks, NewUserRepo, NewGiftRepo)
@@ -0,0 +1,243 @@
+// Package accesslog 按 IDL 把 thrift 请求参数与响应结果解码为可以写入访问日志的结构，并对敏感字段脱敏
+package accesslog
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// Redacted 脱敏字段在日志中的取值
+const Redacted = "[REDACTED]"
+
+// DefaultRedactFields 未配置脱敏字段时默认脱敏的字段
+var DefaultRedactFields = []string{"User.email", "UserDetails.phone"}
+
+// Decoder 按 IDL 解码 binary 协议编码的参数与结果
+//
+// 结构体字段以字段名为键，枚举输出名字，binary 字段只输出长度。
+// 脱敏字段的键为 Struct.field，方法参数与返回值的键为 Service.method.arg，返回值名为 success。
+type Decoder struct {
+	set       *thriftidl.Set
+	functions map[string]*function
+	redact    map[string]bool
+}
+
+// function IDL 中定义的方法及所在的文件
+type function struct {
+	doc *thriftidl.Document
+	fn  *thriftidl.Function
+}
+
+// NewDecoder 由 idl 根目录下的 .thrift 文件创建 Decoder，redact 为空时使用 DefaultRedactFields
+func NewDecoder(redact []string, idl ...fs.FS) (*Decoder, error) {
+	var docs []*thriftidl.Document
+	for _, fsys := range idl {
+		files, err := fs.Glob(fsys, "*.thrift")
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			src, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, err
+			}
+			doc, err := thriftidl.Parse(path.Base(file), src)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, doc)
+		}
+	}
+	if len(redact) == 0 {
+		redact = DefaultRedactFields
+	}
+	d := &Decoder{
+		set:       thriftidl.NewSet(docs...),
+		functions: map[string]*function{},
+		redact:    make(map[string]bool, len(redact)),
+	}
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			for _, fn := range svc.Functions {
+				d.functions[svc.Name+"."+fn.Name] = &function{doc: doc, fn: fn}
+			}
+		}
+	}
+	for _, field := range redact {
+		d.redact[field] = true
+	}
+	return d, nil
+}
+
+// Args 解码 operation（Service.method）的请求参数，data 为参数结构体，不含消息头
+func (d *Decoder) Args(ctx context.Context, operation string, data []byte) (map[string]any, error) {
+	f, ok := d.functions[operation]
+	if !ok {
+		return nil, fmt.Errorf("unknown operation %s", operation)
+	}
+	return d.readStruct(ctx, newProtocol(data), f.doc, operation, f.fn.Args)
+}
+
+// Result 解码 operation 的响应消息，异常响应返回 nil
+func (d *Decoder) Result(ctx context.Context, operation string, data []byte) (map[string]any, error) {
+	f, ok := d.functions[operation]
+	if !ok {
+		return nil, fmt.Errorf("unknown operation %s", operation)
+	}
+	p := newProtocol(data)
+	_, typeID, _, err := p.ReadMessageBegin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if typeID != thrift.REPLY {
+		return nil, nil
+	}
+	fields := f.fn.Throws
+	if f.fn.Returns != nil {
+		fields = append([]*thriftidl.Field{{ID: 0, Name: "success", Type: f.fn.Returns}}, fields...)
+	}
+	return d.readStruct(ctx, p, f.doc, operation, fields)
+}
+
+func newProtocol(data []byte) thrift.TProtocol {
+	buf := thrift.NewTMemoryBuffer()
+	buf.Write(data)
+	return thrift.NewTBinaryProtocolConf(buf, nil)
+}
+
+// readStruct 读取结构体，IDL 中没有的字段直接跳过
+func (d *Decoder) readStruct(ctx context.Context, p thrift.TProtocol, doc *thriftidl.Document, name string, fields []*thriftidl.Field) (map[string]any, error) {
+	if _, err := p.ReadStructBegin(ctx); err != nil {
+		return nil, err
+	}
+	byID := make(map[int16]*thriftidl.Field, len(fields))
+	for _, f := range fields {
+		byID[int16(f.ID)] = f
+	}
+	values := map[string]any{}
+	for {
+		_, typeID, id, err := p.ReadFieldBegin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if typeID == thrift.STOP {
+			break
+		}
+		f, ok := byID[id]
+		if !ok {
+			if err := p.Skip(ctx, typeID); err != nil {
+				return nil, err
+			}
+		} else if d.redact[name+"."+f.Name] {
+			if err := p.Skip(ctx, typeID); err != nil {
+				return nil, err
+			}
+			values[f.Name] = Redacted
+		} else {
+			v, err := d.readValue(ctx, p, doc, f.Type, typeID)
+			if err != nil {
+				return nil, err
+			}
+			values[f.Name] = v
+		}
+		if err := p.ReadFieldEnd(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return values, p.ReadStructEnd(ctx)
+}
+
+// readValue 按线上的类型读取值，IDL 类型只用于结构体字段名、枚举名与区分 binary
+func (d *Decoder) readValue(ctx context.Context, p thrift.TProtocol, doc *thriftidl.Document, t *thriftidl.Type, typeID thrift.TType) (any, error) {
+	if t == nil {
+		return nil, p.Skip(ctx, typeID)
+	}
+	r, err := d.set.Resolve(doc, t)
+	if err != nil {
+		return nil, p.Skip(ctx, typeID)
+	}
+	switch typeID {
+	case thrift.BOOL:
+		return p.ReadBool(ctx)
+	case thrift.BYTE:
+		return p.ReadByte(ctx)
+	case thrift.I16:
+		return p.ReadI16(ctx)
+	case thrift.I32:
+		v, err := p.ReadI32(ctx)
+		if err != nil || r.Enum == nil {
+			return v, err
+		}
+		for _, ev := range r.Enum.Values {
+			if ev.Value == int64(v) {
+				return ev.Name, nil
+			}
+		}
+		return v, nil
+	case thrift.I64:
+		return p.ReadI64(ctx)
+	case thrift.DOUBLE:
+		return p.ReadDouble(ctx)
+	case thrift.STRING:
+		if r.Type.Name == thriftidl.TypeBinary {
+			b, err := p.ReadBinary(ctx)
+			return fmt.Sprintf("[%d bytes]", len(b)), err
+		}
+		return p.ReadString(ctx)
+	case thrift.STRUCT:
+		if r.Struct == nil {
+			return nil, p.Skip(ctx, typeID)
+		}
+		return d.readStruct(ctx, p, r.Doc, r.Struct.Name, r.Struct.Fields)
+	case thrift.LIST, thrift.SET:
+		var (
+			elemType thrift.TType
+			size     int
+		)
+		if typeID == thrift.LIST {
+			elemType, size, err = p.ReadListBegin(ctx)
+		} else {
+			elemType, size, err = p.ReadSetBegin(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			v, err := d.readValue(ctx, p, r.Doc, r.Type.Value, elemType)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		if typeID == thrift.LIST {
+			return values, p.ReadListEnd(ctx)
+		}
+		return values, p.ReadSetEnd(ctx)
+	case thrift.MAP:
+		keyType, valueType, size, err := p.ReadMapBegin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]any, size)
+		for i := 0; i < size; i++ {
+			k, err := d.readValue(ctx, p, r.Doc, r.Type.Key, keyType)
+			if err != nil {
+				return nil, err
+			}
+			v, err := d.readValue(ctx, p, r.Doc, r.Type.Value, valueType)
+			if err != nil {
+				return nil, err
+			}
+			values[fmt.Sprint(k)] = v
+		}
+		return values, p.ReadMapEnd(ctx)
+	}
+	return nil, p.Skip(ctx, typeID)
+}
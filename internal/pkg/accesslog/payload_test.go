@@ -0,0 +1,111 @@
+package accesslog
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"aboveThriftRPC/api"
+	"aboveThriftRPC/api/gen-go/user_service"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+func testUser() *user_service.User {
+	phone := "13800000000"
+	return &user_service.User{
+		ID:      1,
+		Name:    "alice",
+		Email:   "alice@example.com",
+		Role:    user_service.UserRole_ADMIN,
+		Details: &user_service.UserDetails{Phone: &phone},
+		Tags:    []string{"vip"},
+		Avatar:  []byte("png-data"),
+	}
+}
+
+// TestDecoder 测试按 IDL 解码参数与结果，敏感字段脱敏，binary 只记录长度
+func TestDecoder(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDecoder(nil, api.IDL)
+	if err != nil {
+		t.Fatalf("创建 Decoder 失败: %v", err)
+	}
+
+	buf := thrift.NewTMemoryBuffer()
+	args := &user_service.UserServiceEchoDataArgs{ClientData: []byte("hello"), User: testUser()}
+	if err := args.Write(ctx, thrift.NewTBinaryProtocolConf(buf, nil)); err != nil {
+		t.Fatalf("编码参数失败: %v", err)
+	}
+	req, err := d.Args(ctx, "UserService.echoData", buf.Bytes())
+	if err != nil {
+		t.Fatalf("解码参数失败: %v", err)
+	}
+	want := map[string]any{
+		"clientData": "[5 bytes]",
+		"user": map[string]any{
+			"id":         int64(1),
+			"name":       "alice",
+			"email":      Redacted,
+			"age":        int32(0),
+			"role":       "ADMIN",
+			"details":    map[string]any{"phone": Redacted},
+			"tags":       []any{"vip"},
+			"attributes": map[string]any{},
+			"avatar":     "[8 bytes]",
+		},
+	}
+	if !reflect.DeepEqual(req, want) {
+		t.Fatalf("解码参数不一致:\n got: %#v\nwant: %#v", req, want)
+	}
+
+	buf = thrift.NewTMemoryBuffer()
+	prot := thrift.NewTBinaryProtocolConf(buf, nil)
+	prot.WriteMessageBegin(ctx, "echoData", thrift.REPLY, 1)
+	result := &user_service.UserServiceEchoDataResult{Success: &user_service.EchoResponse{ServerId: 2, ClientData: []byte("hello"), User: testUser()}}
+	if err := result.Write(ctx, prot); err != nil {
+		t.Fatalf("编码结果失败: %v", err)
+	}
+	prot.WriteMessageEnd(ctx)
+	resp, err := d.Result(ctx, "UserService.echoData", buf.Bytes())
+	if err != nil {
+		t.Fatalf("解码结果失败: %v", err)
+	}
+	success, _ := resp["success"].(map[string]any)
+	if success["serverId"] != int64(2) || success["clientData"] != "[5 bytes]" || success["user"].(map[string]any)["email"] != Redacted {
+		t.Fatalf("解码结果不一致: %#v", resp)
+	}
+
+	// 异常响应不记录结果
+	buf = thrift.NewTMemoryBuffer()
+	prot = thrift.NewTBinaryProtocolConf(buf, nil)
+	prot.WriteMessageBegin(ctx, "echoData", thrift.EXCEPTION, 1)
+	thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "boom").Write(ctx, prot)
+	if resp, err := d.Result(ctx, "UserService.echoData", buf.Bytes()); err != nil || resp != nil {
+		t.Fatalf("异常响应期望返回 nil, 实际: %v, %v", resp, err)
+	}
+
+	if _, err := d.Args(ctx, "UserService.unknown", nil); err == nil {
+		t.Fatal("期望未定义的方法返回错误")
+	}
+}
+
+// TestDecoderRedactFields 测试自定义脱敏字段替换默认值，方法参数同样可以脱敏
+func TestDecoderRedactFields(t *testing.T) {
+	ctx := context.Background()
+	d, err := NewDecoder([]string{"User.name", "UserService.echoData.clientData"}, api.IDL)
+	if err != nil {
+		t.Fatalf("创建 Decoder 失败: %v", err)
+	}
+	buf := thrift.NewTMemoryBuffer()
+	args := &user_service.UserServiceEchoDataArgs{ClientData: []byte("hi"), User: testUser()}
+	args.Write(ctx, thrift.NewTBinaryProtocolConf(buf, nil))
+	req, err := d.Args(ctx, "UserService.echoData", buf.Bytes())
+	if err != nil {
+		t.Fatalf("解码参数失败: %v", err)
+	}
+	user, _ := req["user"].(map[string]any)
+	if req["clientData"] != Redacted || user["name"] != Redacted || user["email"] != "alice@example.com" {
+		t.Fatalf("参数脱敏不一致: %#v", req)
+	}
+}
@@ -0,0 +1,23 @@
+package health
+
+import (
+	gen "aboveThriftRPC/api/gen-go/health"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+var _ Health = (*Client)(nil)
+
+// Client 健康检查服务的客户端
+type Client = gen.HealthClient
+
+// NewClient 使用已经指定了服务名的 TClient 创建客户端
+func NewClient(c thrift.TClient) *Client {
+	return gen.NewHealthClient(c)
+}
+
+// NewClientProtocol 在 protocol 上使用多路协议调用 Health 服务
+func NewClientProtocol(protocol thrift.TProtocol) *Client {
+	multiplexed := thrift.NewTMultiplexedProtocol(protocol, ServiceName)
+	return NewClient(thrift.NewTStandardClient(multiplexed, multiplexed))
+}
@@ -0,0 +1,41 @@
+// Package health 内置的 thrift 健康检查服务，报告各服务的状态与依赖（Redis 等）的检查结果
+//
+// 接口定义见 health.thrift，结构体、处理器与客户端由 thrift 编译器生成到 api/gen-go/health：
+//
+//	thrift --gen go:package_prefix=aboveThriftRPC/api/gen-go/ -out ./api/gen-go ./internal/pkg/health/health.thrift
+package health
+
+import (
+	"embed"
+
+	gen "aboveThriftRPC/api/gen-go/health"
+)
+
+// ServiceName 健康检查服务在多路处理器中注册的服务名
+const ServiceName = "Health"
+
+// IDL 健康检查服务的 .thrift 文件，供反射服务查询
+//
+//go:embed health.thrift
+var IDL embed.FS
+
+type (
+	// Health 健康检查服务接口
+	Health = gen.Health
+	// ServingStatus 服务状态，JSON 中使用状态名
+	ServingStatus = gen.ServingStatus
+	// HealthCheckResponse 查询结果
+	HealthCheckResponse = gen.HealthCheckResponse
+)
+
+const (
+	ServingStatus_UNKNOWN         = gen.ServingStatus_UNKNOWN
+	ServingStatus_SERVING         = gen.ServingStatus_SERVING
+	ServingStatus_NOT_SERVING     = gen.ServingStatus_NOT_SERVING
+	ServingStatus_SERVICE_UNKNOWN = gen.ServingStatus_SERVICE_UNKNOWN
+)
+
+// ServingStatusFromString 由状态名解析状态
+func ServingStatusFromString(s string) (ServingStatus, error) {
+	return gen.ServingStatusFromString(s)
+}
@@ -0,0 +1,22 @@
+namespace go health
+
+// 服务状态，与 grpc.health.v1 保持一致
+enum ServingStatus {
+  UNKNOWN = 0,
+  SERVING = 1,
+  NOT_SERVING = 2,
+  SERVICE_UNKNOWN = 3,  // 服务未注册
+}
+
+struct HealthCheckResponse {
+  1: ServingStatus status,
+}
+
+// 健康检查
+service Health {
+  // 查询服务状态，service 为空时查询整体状态，整体状态包含依赖检查
+  HealthCheckResponse Check(1: string service),
+
+  // 列出各服务的状态
+  map<string, ServingStatus> List(),
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// loopbackClient 在内存中把请求交给处理器，用于测试编解码
+type loopbackClient struct {
+	processor thrift.TProcessor
+}
+
+func (c *loopbackClient) Call(ctx context.Context, method string, args, result thrift.TStruct) (thrift.ResponseMeta, error) {
+	in, out := thrift.NewTMemoryBuffer(), thrift.NewTMemoryBuffer()
+	iprot, oprot := thrift.NewTBinaryProtocolConf(in, nil), thrift.NewTBinaryProtocolConf(out, nil)
+	if err := iprot.WriteMessageBegin(ctx, method, thrift.CALL, 1); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if err := args.Write(ctx, iprot); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if err := iprot.WriteMessageEnd(ctx); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if _, err := c.processor.Process(ctx, iprot, oprot); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if _, _, _, err := oprot.ReadMessageBegin(ctx); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	return thrift.ResponseMeta{}, result.Read(ctx, oprot)
+}
+
+func TestHealth(t *testing.T) {
+	redisErr := errors.New("connection refused")
+	var redisDown atomic.Bool
+	blocked := make(chan struct{})
+	t.Cleanup(func() { close(blocked) })
+	srv := NewServer(Checks{
+		"redis": func(ctx context.Context) error {
+			if redisDown.Load() {
+				return redisErr
+			}
+			return nil
+		},
+		// 不响应 ctx 的检查按超时处理
+		"slow": func(ctx context.Context) error {
+			if redisDown.Load() {
+				<-blocked
+			}
+			return nil
+		},
+	})
+	srv.timeout = 50 * time.Millisecond
+	srv.SetServingStatus("GiftService", ServingStatus_SERVING)
+	client := NewClient(&loopbackClient{processor: NewHealthProcessor(srv)})
+	ctx := context.Background()
+
+	check := func(service string, want ServingStatus) {
+		t.Helper()
+		resp, err := client.Check(ctx, service)
+		if err != nil || resp.Status != want {
+			t.Fatalf("Check(%q) 返回 %v, %v, 期望 %v", service, resp, err, want)
+		}
+	}
+	check("", ServingStatus_SERVING)
+	check("GiftService", ServingStatus_SERVING)
+	check("Missing", ServingStatus_SERVICE_UNKNOWN)
+
+	statuses, err := client.List(ctx)
+	if err != nil || !reflect.DeepEqual(statuses, map[string]ServingStatus{"GiftService": ServingStatus_SERVING}) {
+		t.Fatalf("List 返回 %v, %v", statuses, err)
+	}
+
+	// 依赖不可用时整体不可用，服务自身的状态不变
+	redisDown.Store(true)
+	check("", ServingStatus_NOT_SERVING)
+	check("GiftService", ServingStatus_SERVING)
+	report := srv.Readiness(ctx)
+	want := map[string]string{"redis": redisErr.Error(), "slow": "check timed out"}
+	if report.Status != ServingStatus_NOT_SERVING || !reflect.DeepEqual(report.Checks, want) {
+		t.Fatalf("Readiness 返回 %+v", report)
+	}
+	redisDown.Store(false)
+
+	// 任一服务不可用时整体不可用
+	srv.SetServingStatus("UserService", ServingStatus_NOT_SERVING)
+	check("", ServingStatus_NOT_SERVING)
+	srv.SetServingStatus("UserService", ServingStatus_SERVING)
+	check("", ServingStatus_SERVING)
+
+	// 停止期间全部 NOT_SERVING，并忽略新的状态
+	srv.Shutdown()
+	srv.SetServingStatus("GiftService", ServingStatus_SERVING)
+	check("", ServingStatus_NOT_SERVING)
+	check("GiftService", ServingStatus_NOT_SERVING)
+	srv.Resume()
+	check("", ServingStatus_SERVING)
+	check("GiftService", ServingStatus_SERVING)
+}
+
+func TestServingStatus(t *testing.T) {
+	for _, s := range []ServingStatus{ServingStatus_UNKNOWN, ServingStatus_SERVING, ServingStatus_NOT_SERVING, ServingStatus_SERVICE_UNKNOWN} {
+		got, err := ServingStatusFromString(s.String())
+		if err != nil || got != s {
+			t.Fatalf("ServingStatusFromString(%q) 返回 %v, %v", s, got, err)
+		}
+	}
+	if _, err := ServingStatusFromString("NOPE"); err == nil {
+		t.Fatal("期望解析失败")
+	}
+}
@@ -0,0 +1,12 @@
+package health
+
+import (
+	gen "aboveThriftRPC/api/gen-go/health"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// NewHealthProcessor 创建健康检查服务的处理器
+func NewHealthProcessor(handler Health) thrift.TProcessor {
+	return gen.NewHealthProcessor(handler)
+}
@@ -0,0 +1,150 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var _ Health = (*Server)(nil)
+
+// checkTimeout 单个依赖检查默认的超时时间
+const checkTimeout = time.Second
+
+// Check 依赖检查，返回 nil 表示依赖可用
+type Check func(ctx context.Context) error
+
+// Checks 按名字索引的依赖检查，如 redis
+type Checks map[string]Check
+
+// Report 就绪检查的结果，Checks 中检查通过的依赖为 ok，否则为错误信息
+type Report struct {
+	Status   ServingStatus            `json:"status"`
+	Services map[string]ServingStatus `json:"services"`
+	Checks   map[string]string        `json:"checks,omitempty"`
+}
+
+// Server 健康检查服务的实现，记录各服务的状态，整体状态由依赖检查决定
+type Server struct {
+	checks  Checks
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	shutdown bool
+	statuses map[string]ServingStatus
+}
+
+// NewServer 创建健康检查服务
+func NewServer(checks Checks) *Server {
+	return &Server{checks: checks, timeout: checkTimeout, statuses: map[string]ServingStatus{}}
+}
+
+// SetServingStatus 设置服务的状态，Shutdown 之后的设置会被忽略，直到 Resume
+func (s *Server) SetServingStatus(service string, status ServingStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shutdown {
+		return
+	}
+	s.statuses[service] = status
+}
+
+// Shutdown 把所有服务置为 NOT_SERVING，服务端停止前调用，让负载均衡尽快摘除实例
+func (s *Server) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdown = true
+	for service := range s.statuses {
+		s.statuses[service] = ServingStatus_NOT_SERVING
+	}
+}
+
+// Resume 把所有服务恢复为 SERVING
+func (s *Server) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdown = false
+	for service := range s.statuses {
+		s.statuses[service] = ServingStatus_SERVING
+	}
+}
+
+func (s *Server) Check(ctx context.Context, service string) (*HealthCheckResponse, error) {
+	if service == "" {
+		return &HealthCheckResponse{Status: s.Readiness(ctx).Status}, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status, ok := s.statuses[service]
+	if !ok {
+		return &HealthCheckResponse{Status: ServingStatus_SERVICE_UNKNOWN}, nil
+	}
+	return &HealthCheckResponse{Status: status}, nil
+}
+
+func (s *Server) List(ctx context.Context) (map[string]ServingStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	statuses := make(map[string]ServingStatus, len(s.statuses))
+	for service, status := range s.statuses {
+		statuses[service] = status
+	}
+	return statuses, nil
+}
+
+// Readiness 执行全部依赖检查，所有服务都是 SERVING 且依赖都可用时整体状态为 SERVING
+func (s *Server) Readiness(ctx context.Context) *Report {
+	services, _ := s.List(ctx)
+	report := &Report{Status: ServingStatus_SERVING, Services: services}
+	for _, status := range services {
+		if status != ServingStatus_SERVING {
+			report.Status = ServingStatus_NOT_SERVING
+		}
+	}
+
+	if len(s.checks) == 0 {
+		return report
+	}
+	report.Checks = make(map[string]string, len(s.checks))
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := runCheck(ctx, check, s.timeout)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				report.Checks[name] = err.Error()
+				report.Status = ServingStatus_NOT_SERVING
+				return
+			}
+			report.Checks[name] = "ok"
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// runCheck 在超时时间内执行检查，不响应 ctx 的检查也会按时返回
+func runCheck(ctx context.Context, check Check, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	errc := make(chan error, 1)
+	go func() {
+		errc <- check(ctx)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return errors.New("check timed out")
+		}
+		return ctx.Err()
+	}
+}
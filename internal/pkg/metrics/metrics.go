@@ -0,0 +1,170 @@
+// Package metrics thrift 服务端与客户端的 Prometheus 指标，注册在默认的 prometheus.DefaultRegisterer 上
+package metrics
+
+import (
+	"sync"
+
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CodeOK 请求成功时的 code 标签
+const CodeOK = "OK"
+
+var (
+	// ServerRequests 服务端处理的请求数，code 为 OK 或错误的 reason（如 TIMEOUT、IDL 异常的类型名）
+	ServerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "thrift",
+		Subsystem: "server",
+		Name:      "requests_total",
+		Help:      "Total number of thrift requests handled by the server.",
+	}, []string{"service", "method", "code"})
+	// ServerDuration 服务端请求的处理耗时
+	ServerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "thrift",
+		Subsystem: "server",
+		Name:      "request_duration_seconds",
+		Help:      "Thrift request latency on the server in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+	// ServerInFlight 服务端正在处理的请求数
+	ServerInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "thrift",
+		Subsystem: "server",
+		Name:      "requests_in_flight",
+		Help:      "Number of thrift requests currently being handled by the server.",
+	}, []string{"service", "method"})
+	// ServerAbandonedHandlers 已经向客户端返回 TIMEOUT、但没有响应 ctx 取消仍在运行的处理函数数
+	ServerAbandonedHandlers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "thrift",
+		Subsystem: "server",
+		Name:      "abandoned_handlers",
+		Help:      "Number of timed out thrift handlers still running after the client received TIMEOUT.",
+	}, []string{"service", "method"})
+	// ServerConnectionsAccepted 服务端接受的连接数
+	ServerConnectionsAccepted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "thrift",
+		Subsystem: "server",
+		Name:      "connections_accepted_total",
+		Help:      "Total number of connections accepted by the thrift server.",
+	})
+	// ServerConnectionsClosed 服务端关闭的连接数，包括超出上限被拒绝的连接
+	ServerConnectionsClosed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "thrift",
+		Subsystem: "server",
+		Name:      "connections_closed_total",
+		Help:      "Total number of connections closed by the thrift server.",
+	})
+
+	// ClientRequests 客户端发起的请求数
+	ClientRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "thrift",
+		Subsystem: "client",
+		Name:      "requests_total",
+		Help:      "Total number of thrift requests sent by the client.",
+	}, []string{"service", "method", "code"})
+	// ClientDuration 客户端请求的耗时
+	ClientDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "thrift",
+		Subsystem: "client",
+		Name:      "request_duration_seconds",
+		Help:      "Thrift request latency on the client in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+	// ClientInFlight 客户端等待响应的请求数
+	ClientInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "thrift",
+		Subsystem: "client",
+		Name:      "requests_in_flight",
+		Help:      "Number of thrift requests currently waiting for a response.",
+	}, []string{"service", "method"})
+	// ClientPoolBorrowWait 从连接池借出连接的等待时间
+	ClientPoolBorrowWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "thrift",
+		Subsystem: "client_pool",
+		Name:      "borrow_wait_seconds",
+		Help:      "Time spent waiting to borrow a connection from the pool in seconds.",
+		Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5},
+	}, []string{"addr"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		ServerRequests, ServerDuration, ServerInFlight, ServerAbandonedHandlers, ServerConnectionsAccepted, ServerConnectionsClosed,
+		ClientRequests, ClientDuration, ClientInFlight, ClientPoolBorrowWait,
+		pools,
+	)
+}
+
+// Code 返回错误对应的 code 标签，与 HTTP 网关返回的 reason 一致
+func Code(err error) string {
+	if err == nil {
+		return CodeOK
+	}
+	if reason := errors.FromError(thriftx.FromError(err)).Reason; reason != "" {
+		return reason
+	}
+	return "UNKNOWN"
+}
+
+// PoolStats 返回连接池的活跃连接数、空闲连接数与等待借出的请求数
+type PoolStats func() (active, idle, waiters int)
+
+var pools = &poolCollector{
+	pools:   map[*PoolStats]string{},
+	active:  prometheus.NewDesc("thrift_client_pool_active", "Number of connections borrowed from the pool.", []string{"addr"}, nil),
+	idle:    prometheus.NewDesc("thrift_client_pool_idle", "Number of idle connections in the pool.", []string{"addr"}, nil),
+	waiters: prometheus.NewDesc("thrift_client_pool_waiters", "Number of callers waiting to borrow a connection.", []string{"addr"}, nil),
+}
+
+// RegisterPool 采集连接池的状态，同一地址的多个连接池合并统计，返回的函数用于在连接池关闭时取消采集
+func RegisterPool(addr string, stats PoolStats) (unregister func()) {
+	key := &stats
+	pools.mu.Lock()
+	pools.pools[key] = addr
+	pools.mu.Unlock()
+	return func() {
+		pools.mu.Lock()
+		delete(pools.pools, key)
+		pools.mu.Unlock()
+	}
+}
+
+// poolCollector 在采集时读取各连接池的状态
+type poolCollector struct {
+	mu    sync.Mutex
+	pools map[*PoolStats]string
+
+	active, idle, waiters *prometheus.Desc
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.active
+	ch <- c.idle
+	ch <- c.waiters
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	type total struct{ active, idle, waiters int }
+	totals := map[string]*total{}
+	c.mu.Lock()
+	for stats, addr := range c.pools {
+		active, idle, waiters := (*stats)()
+		t, ok := totals[addr]
+		if !ok {
+			t = &total{}
+			totals[addr] = t
+		}
+		t.active += active
+		t.idle += idle
+		t.waiters += waiters
+	}
+	c.mu.Unlock()
+	for addr, t := range totals {
+		ch <- prometheus.MustNewConstMetric(c.active, prometheus.GaugeValue, float64(t.active), addr)
+		ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(t.idle), addr)
+		ch <- prometheus.MustNewConstMetric(c.waiters, prometheus.GaugeValue, float64(t.waiters), addr)
+	}
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"aboveThriftRPC/internal/pkg/reflection"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, CodeOK},
+		{thriftx.NewTimeoutException("slow"), "TIMEOUT"},
+		{thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "boom"), "INTERNAL_ERROR"},
+		{&reflection.ReflectionException{Message: "missing"}, "ReflectionException"},
+		{context.Canceled, "CANCELED"},
+		{errors.New("boom"), "UNKNOWN"},
+	}
+	for _, tt := range tests {
+		if got := Code(tt.err); got != tt.want {
+			t.Errorf("Code(%v) = %q, 期望 %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestPoolCollector(t *testing.T) {
+	unregister1 := RegisterPool("127.0.0.1:9000", func() (int, int, int) { return 2, 1, 3 })
+	unregister2 := RegisterPool("127.0.0.1:9000", func() (int, int, int) { return 1, 0, 0 })
+	defer unregister2()
+
+	// 同一地址的连接池合并统计
+	want := `
+# HELP thrift_client_pool_active Number of connections borrowed from the pool.
+# TYPE thrift_client_pool_active gauge
+thrift_client_pool_active{addr="127.0.0.1:9000"} 3
+# HELP thrift_client_pool_waiters Number of callers waiting to borrow a connection.
+# TYPE thrift_client_pool_waiters gauge
+thrift_client_pool_waiters{addr="127.0.0.1:9000"} 3
+`
+	if err := testutil.CollectAndCompare(pools, strings.NewReader(want), "thrift_client_pool_active", "thrift_client_pool_waiters"); err != nil {
+		t.Fatal(err)
+	}
+	unregister1()
+	if n := testutil.CollectAndCount(pools, "thrift_client_pool_active"); n != 1 {
+		t.Fatalf("期望 1 个连接池指标, 实际 %d", n)
+	}
+	unregister2()
+	if n := testutil.CollectAndCount(pools); n != 0 {
+		t.Fatalf("取消采集后期望没有指标, 实际 %d", n)
+	}
+}
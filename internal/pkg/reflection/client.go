@@ -0,0 +1,42 @@
+package reflection
+
+import (
+	"context"
+
+	gen "aboveThriftRPC/api/gen-go/reflection"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+var _ Reflection = (*Client)(nil)
+
+// Client 反射服务的客户端，供工具在运行时查询服务端的接口
+type Client = gen.ReflectionClient
+
+// NewClient 使用已经指定了服务名的 TClient 创建客户端
+func NewClient(c thrift.TClient) *Client {
+	return gen.NewReflectionClient(c)
+}
+
+// NewClientProtocol 在 protocol 上使用多路协议调用 Reflection 服务
+func NewClientProtocol(protocol thrift.TProtocol) *Client {
+	multiplexed := thrift.NewTMultiplexedProtocol(protocol, ServiceName)
+	return NewClient(thrift.NewTStandardClient(multiplexed, multiplexed))
+}
+
+// Discover 查询服务端注册的全部服务及其方法
+func Discover(ctx context.Context, r Reflection) ([]*ServiceInfo, error) {
+	services, err := r.ListServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]*ServiceInfo, 0, len(services))
+	for _, service := range services {
+		info, err := r.DescribeService(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
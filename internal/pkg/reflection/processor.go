@@ -0,0 +1,12 @@
+package reflection
+
+import (
+	gen "aboveThriftRPC/api/gen-go/reflection"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// NewReflectionProcessor 创建反射服务的处理器
+func NewReflectionProcessor(handler Reflection) thrift.TProcessor {
+	return gen.NewReflectionProcessor(handler)
+}
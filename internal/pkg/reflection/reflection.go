@@ -0,0 +1,31 @@
+// Package reflection 内置的 thrift 反射服务，查询服务端注册的服务、方法、IDL 签名与 .thrift 源码
+//
+// 接口定义见 reflection.thrift，结构体、处理器与客户端由 thrift 编译器生成到 api/gen-go/reflection：
+//
+//	thrift --gen go:package_prefix=aboveThriftRPC/api/gen-go/ -out ./api/gen-go ./internal/pkg/reflection/reflection.thrift
+package reflection
+
+import (
+	_ "embed"
+
+	gen "aboveThriftRPC/api/gen-go/reflection"
+)
+
+// ServiceName 反射服务在多路处理器中注册的服务名
+const ServiceName = "Reflection"
+
+//go:embed reflection.thrift
+var reflectionIDL []byte
+
+type (
+	// Reflection 反射服务接口
+	Reflection = gen.Reflection
+	// FieldInfo 字段、参数或异常声明
+	FieldInfo = gen.FieldInfo
+	// MethodInfo 方法的 IDL 签名
+	MethodInfo = gen.MethodInfo
+	// ServiceInfo 服务及其方法
+	ServiceInfo = gen.ServiceInfo
+	// ReflectionException 服务或文件不存在
+	ReflectionException = gen.ReflectionException
+)
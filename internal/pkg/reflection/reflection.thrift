@@ -0,0 +1,44 @@
+namespace go reflection
+
+// 字段、参数或异常声明
+struct FieldInfo {
+  1: i16 id,
+  2: string name,
+  3: string type,          // IDL 中的类型，如 list<Gift>
+  4: string requiredness,  // required、optional 或空
+}
+
+// 方法的 IDL 签名
+struct MethodInfo {
+  1: string name,
+  2: string signature,     // 如 Gift SendGift(1: i64 senderId)
+  3: string returnType,    // 没有返回值时为 void
+  4: list<FieldInfo> args,
+  5: list<FieldInfo> throws,
+  6: bool oneway,
+  7: string doc,
+}
+
+// 服务及其方法
+struct ServiceInfo {
+  1: string name,
+  2: string filename,      // 定义服务的 .thrift 文件，找不到 IDL 时为空
+  3: list<MethodInfo> methods,
+  4: string doc,
+}
+
+exception ReflectionException {
+  1: string message,
+}
+
+// 查询服务端注册的服务、方法与 IDL
+service Reflection {
+  // 列出注册的服务名
+  list<string> ListServices(),
+
+  // 查询服务的方法与签名
+  ServiceInfo DescribeService(1: string service) throws (1: ReflectionException err),
+
+  // 返回 .thrift 文件的源码
+  string GetFileSource(1: string filename) throws (1: ReflectionException err),
+}
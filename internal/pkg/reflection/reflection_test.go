@@ -0,0 +1,157 @@
+package reflection
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	gen "aboveThriftRPC/api/gen-go/reflection"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+const testIDL = `namespace go demo
+
+// 示例服务
+service Demo {
+  // 查询
+  string Get(1: required i64 id, 2: optional string name) throws (1: NotFound notFound),
+  oneway void Ping(),
+}
+
+exception NotFound { 1: string message }
+`
+
+// loopbackClient 在内存中把请求交给处理器，用于测试编解码
+type loopbackClient struct {
+	processor thrift.TProcessor
+}
+
+func (c *loopbackClient) Call(ctx context.Context, method string, args, result thrift.TStruct) (thrift.ResponseMeta, error) {
+	in, out := thrift.NewTMemoryBuffer(), thrift.NewTMemoryBuffer()
+	iprot, oprot := thrift.NewTBinaryProtocolConf(in, nil), thrift.NewTBinaryProtocolConf(out, nil)
+	if err := iprot.WriteMessageBegin(ctx, method, thrift.CALL, 1); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if err := args.Write(ctx, iprot); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if err := iprot.WriteMessageEnd(ctx); err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if _, err := c.processor.Process(ctx, iprot, oprot); err != nil {
+		var ae thrift.TApplicationException
+		if !errors.As(err, &ae) {
+			return thrift.ResponseMeta{}, err
+		}
+	}
+	_, typ, _, err := oprot.ReadMessageBegin(ctx)
+	if err != nil {
+		return thrift.ResponseMeta{}, err
+	}
+	if typ == thrift.EXCEPTION {
+		x := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "")
+		if err := x.Read(ctx, oprot); err != nil {
+			return thrift.ResponseMeta{}, err
+		}
+		return thrift.ResponseMeta{}, x
+	}
+	return thrift.ResponseMeta{}, result.Read(ctx, oprot)
+}
+
+// demoProcessor 只注册方法名的处理器
+type demoProcessor map[string]thrift.TProcessorFunction
+
+func (p demoProcessor) Process(ctx context.Context, in, out thrift.TProtocol) (bool, thrift.TException) {
+	return false, nil
+}
+
+func (p demoProcessor) ProcessorMap() map[string]thrift.TProcessorFunction {
+	return p
+}
+
+func (p demoProcessor) AddToProcessorMap(key string, processor thrift.TProcessorFunction) {
+	p[key] = processor
+}
+
+// newTestClient 创建注册了 Demo 服务的反射服务，返回经过编解码的客户端
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+	processor := thrift.NewTMultiplexedProcessor()
+	demo := demoProcessor{"Get": nil, "Ping": nil, "Extra": nil}
+	processor.RegisterProcessor("Demo", demo)
+	srv, err := NewServer(processor, fstest.MapFS{"demo.thrift": {Data: []byte(testIDL)}})
+	if err != nil {
+		t.Fatalf("创建反射服务失败: %v", err)
+	}
+	reflection := NewReflectionProcessor(srv)
+	processor.RegisterProcessor(ServiceName, reflection)
+	return NewClient(&loopbackClient{processor: reflection})
+}
+
+func TestReflection(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	services, err := client.ListServices(ctx)
+	if err != nil || !reflect.DeepEqual(services, []string{"Demo", ServiceName}) {
+		t.Fatalf("ListServices 返回 %v, %v", services, err)
+	}
+
+	info, err := client.DescribeService(ctx, "Demo")
+	if err != nil {
+		t.Fatalf("DescribeService 失败: %v", err)
+	}
+	want := &ServiceInfo{
+		Name:     "Demo",
+		Filename: "demo.thrift",
+		Doc:      "示例服务",
+		Methods: []*MethodInfo{
+			{
+				Name:       "Get",
+				Signature:  "string Get(1: required i64 id, 2: optional string name) throws (1: NotFound notFound)",
+				ReturnType: "string",
+				Args_: []*FieldInfo{
+					{ID: 1, Name: "id", Type: "i64", Requiredness: "required"},
+					{ID: 2, Name: "name", Type: "string", Requiredness: "optional"},
+				},
+				Throws: []*FieldInfo{{ID: 1, Name: "notFound", Type: "NotFound"}},
+				Doc:    "查询",
+			},
+			{Name: "Ping", Signature: "oneway void Ping()", ReturnType: "void", Args_: []*FieldInfo{}, Throws: []*FieldInfo{}, Oneway: true},
+			// IDL 中没有的方法只有方法名
+			{Name: "Extra", Args_: []*FieldInfo{}, Throws: []*FieldInfo{}},
+		},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatalf("DescribeService 返回 %+v, 期望 %+v", info, want)
+	}
+
+	src, err := client.GetFileSource(ctx, "reflection.thrift")
+	if err != nil || src != string(reflectionIDL) {
+		t.Fatalf("GetFileSource 返回 %q, %v", src, err)
+	}
+}
+
+func TestReflectionErrors(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	var rex *ReflectionException
+	if _, err := client.DescribeService(ctx, "Missing"); !errors.As(err, &rex) {
+		t.Fatalf("期望 ReflectionException, 实际: %v", err)
+	}
+	if _, err := client.GetFileSource(ctx, "missing.thrift"); !errors.As(err, &rex) {
+		t.Fatalf("期望 ReflectionException, 实际: %v", err)
+	}
+	var ae thrift.TApplicationException
+	if _, err := client.Client_().Call(ctx, "Missing", &gen.ReflectionListServicesArgs{}, &gen.ReflectionListServicesResult{}); !errors.As(err, &ae) || ae.TypeId() != thrift.UNKNOWN_METHOD {
+		t.Fatalf("期望 UNKNOWN_METHOD, 实际: %v", err)
+	}
+
+	if _, err := NewServer(thrift.NewTMultiplexedProcessor(), fstest.MapFS{"reflection.thrift": {Data: []byte(testIDL)}}); err == nil {
+		t.Fatal("期望重复的 IDL 文件创建失败")
+	}
+}
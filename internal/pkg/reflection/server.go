@@ -0,0 +1,196 @@
+package reflection
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"aboveThriftRPC/internal/pkg/thriftidl"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+var _ Reflection = (*Server)(nil)
+
+// Server 反射服务的实现，服务与方法以多路处理器中注册的为准，签名与注释来自 IDL
+type Server struct {
+	processor thrift.TProcessor
+	services  map[string]*idlService
+	sources   map[string]string
+}
+
+// idlService IDL 中定义的服务及所在的文件
+type idlService struct {
+	filename string
+	service  *thriftidl.Service
+}
+
+// NewServer 创建反射服务，processor 为注册了各个服务的多路处理器，
+// idl 根目录下的 .thrift 文件用于补充方法签名，也可以通过 GetFileSource 获取源码
+func NewServer(processor thrift.TProcessor, idl ...fs.FS) (*Server, error) {
+	s := &Server{
+		processor: processor,
+		services:  map[string]*idlService{},
+		sources:   map[string]string{},
+	}
+	if err := s.addFile("reflection.thrift", reflectionIDL); err != nil {
+		return nil, err
+	}
+	for _, fsys := range idl {
+		files, err := fs.Glob(fsys, "*.thrift")
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			src, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.addFile(path.Base(file), src); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *Server) addFile(filename string, src []byte) error {
+	if _, ok := s.sources[filename]; ok {
+		return fmt.Errorf("重复的 IDL 文件: %s", filename)
+	}
+	doc, err := thriftidl.Parse(filename, src)
+	if err != nil {
+		return err
+	}
+	s.sources[filename] = string(src)
+	for _, svc := range doc.Services {
+		s.services[svc.Name] = &idlService{filename: filename, service: svc}
+	}
+	return nil
+}
+
+func (s *Server) ListServices(ctx context.Context) ([]string, error) {
+	return Services(s.processor), nil
+}
+
+func (s *Server) DescribeService(ctx context.Context, service string) (*ServiceInfo, error) {
+	registered := map[string]bool{}
+	for _, method := range Methods(s.processor, service) {
+		registered[method] = true
+	}
+	if len(registered) == 0 {
+		return nil, &ReflectionException{Message: "服务未注册: " + service}
+	}
+	info := &ServiceInfo{Name: service}
+	if def, ok := s.services[service]; ok {
+		info.Filename = def.filename
+		info.Doc = def.service.Doc
+		for _, fn := range def.service.Functions {
+			if registered[fn.Name] {
+				info.Methods = append(info.Methods, methodInfo(fn))
+				delete(registered, fn.Name)
+			}
+		}
+	}
+	// IDL 中找不到的方法只返回方法名
+	rest := make([]string, 0, len(registered))
+	for method := range registered {
+		rest = append(rest, method)
+	}
+	sort.Strings(rest)
+	for _, method := range rest {
+		info.Methods = append(info.Methods, &MethodInfo{Name: method})
+	}
+	return info, nil
+}
+
+func (s *Server) GetFileSource(ctx context.Context, filename string) (string, error) {
+	src, ok := s.sources[filename]
+	if !ok {
+		return "", &ReflectionException{Message: "文件不存在: " + filename}
+	}
+	return src, nil
+}
+
+// Services 返回多路处理器中注册的服务名，按名字排序
+func Services(processor thrift.TProcessor) []string {
+	seen := make(map[string]struct{})
+	var services []string
+	for name := range processor.ProcessorMap() {
+		service, _, ok := strings.Cut(name, thrift.MULTIPLEXED_SEPARATOR)
+		if _, dup := seen[service]; !ok || dup {
+			continue
+		}
+		seen[service] = struct{}{}
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// Methods 返回多路处理器中某个服务注册的方法名，按名字排序
+func Methods(processor thrift.TProcessor, service string) []string {
+	var methods []string
+	for name := range processor.ProcessorMap() {
+		if method, ok := strings.CutPrefix(name, service+thrift.MULTIPLEXED_SEPARATOR); ok {
+			methods = append(methods, method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func methodInfo(fn *thriftidl.Function) *MethodInfo {
+	m := &MethodInfo{
+		Name:       fn.Name,
+		ReturnType: "void",
+		Args_:      fieldInfos(fn.Args),
+		Throws:     fieldInfos(fn.Throws),
+		Oneway:     fn.Oneway,
+		Doc:        fn.Doc,
+	}
+	if fn.Returns != nil {
+		m.ReturnType = fn.Returns.String()
+	}
+	var b strings.Builder
+	if fn.Oneway {
+		b.WriteString("oneway ")
+	}
+	fmt.Fprintf(&b, "%s %s(%s)", m.ReturnType, fn.Name, fieldList(m.Args_))
+	if len(m.Throws) > 0 {
+		fmt.Fprintf(&b, " throws (%s)", fieldList(m.Throws))
+	}
+	m.Signature = b.String()
+	return m
+}
+
+func fieldInfos(fields []*thriftidl.Field) []*FieldInfo {
+	infos := make([]*FieldInfo, 0, len(fields))
+	for _, f := range fields {
+		info := &FieldInfo{ID: int16(f.ID), Name: f.Name, Type: f.Type.String()}
+		switch f.Requiredness {
+		case thriftidl.Required:
+			info.Requiredness = "required"
+		case thriftidl.Optional:
+			info.Requiredness = "optional"
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// fieldList 按 IDL 的写法拼接参数列表，如 1: i64 senderId, 2: optional string name
+func fieldList(fields []*FieldInfo) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		typ := f.Type
+		if f.Requiredness != "" {
+			typ = f.Requiredness + " " + typ
+		}
+		parts = append(parts, fmt.Sprintf("%d: %s %s", f.ID, typ, f.Name))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,186 @@
+// Package file 基于本地 JSON 文件的服务注册与发现，不依赖 etcd、consul，适合单机部署和测试
+package file
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+var (
+	_ registry.Registrar = (*Registry)(nil)
+	_ registry.Discovery = (*Registry)(nil)
+)
+
+// Option 文件注册中心选项
+type Option func(*Registry)
+
+// WithWatchInterval 设置 Watch 轮询文件的间隔，默认 1s
+func WithWatchInterval(d time.Duration) Option {
+	return func(r *Registry) {
+		r.interval = d
+	}
+}
+
+// Registry 将服务实例保存在一个 JSON 文件中
+//
+// 写入时先写临时文件再重命名，读到的总是完整的内容；同一进程内的并发写入由互斥锁保护，
+// 多个进程同时注册时后写入的一方可能覆盖前者，不适合大规模部署。
+type Registry struct {
+	path     string
+	interval time.Duration
+	mu       sync.Mutex
+}
+
+// New 创建一个文件注册中心，文件不存在时在首次注册时创建
+func New(path string, opts ...Option) *Registry {
+	r := &Registry{path: path, interval: time.Second}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// Register 注册服务实例，ID 与名称相同的实例会被替换
+func (r *Registry) Register(ctx context.Context, service *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	services, err := r.load()
+	if err != nil {
+		return err
+	}
+	services = remove(services, service)
+	return r.save(append(services, service))
+}
+
+// Deregister 注销服务实例
+func (r *Registry) Deregister(ctx context.Context, service *registry.ServiceInstance) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	services, err := r.load()
+	if err != nil {
+		return err
+	}
+	return r.save(remove(services, service))
+}
+
+// GetService 返回指定名称的全部服务实例
+func (r *Registry) GetService(ctx context.Context, serviceName string) ([]*registry.ServiceInstance, error) {
+	r.mu.Lock()
+	services, err := r.load()
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var result []*registry.ServiceInstance
+	for _, s := range services {
+		if s.Name == serviceName {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+// Watch 按间隔轮询文件，服务实例变化时由 Next 返回
+func (r *Registry) Watch(ctx context.Context, serviceName string) (registry.Watcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &watcher{r: r, name: serviceName, ctx: ctx, cancel: cancel}, nil
+}
+
+func (r *Registry) load() ([]*registry.ServiceInstance, error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var services []*registry.ServiceInstance
+	if err := json.Unmarshal(data, &services); err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+func (r *Registry) save(services []*registry.ServiceInstance) error {
+	data, err := json.MarshalIndent(services, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(r.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), r.path)
+}
+
+func remove(services []*registry.ServiceInstance, service *registry.ServiceInstance) []*registry.ServiceInstance {
+	result := services[:0]
+	for _, s := range services {
+		if s.ID != service.ID || s.Name != service.Name {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+type watcher struct {
+	r      *Registry
+	name   string
+	ctx    context.Context
+	cancel context.CancelFunc
+	last   []byte
+}
+
+// Next 首次调用在存在实例时立即返回，之后在实例发生变化时返回
+func (w *watcher) Next() ([]*registry.ServiceInstance, error) {
+	ticker := time.NewTicker(w.r.interval)
+	defer ticker.Stop()
+	for {
+		services, err := w.r.GetService(w.ctx, w.name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(services)
+		if err != nil {
+			return nil, err
+		}
+		if (w.last == nil && len(services) > 0) || (w.last != nil && !bytes.Equal(data, w.last)) {
+			w.last = data
+			return services, nil
+		}
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop 停止监听
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
@@ -0,0 +1,91 @@
+package file
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+// TestRegistry 测试注册、替换、查询与注销
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	r := New(filepath.Join(t.TempDir(), "registry.json"))
+
+	a := &registry.ServiceInstance{ID: "1", Name: "above", Endpoints: []string{"thrift://127.0.0.1:9000"}}
+	b := &registry.ServiceInstance{ID: "2", Name: "above", Endpoints: []string{"thrift://127.0.0.1:9001"}}
+	other := &registry.ServiceInstance{ID: "1", Name: "other", Endpoints: []string{"thrift://127.0.0.1:9002"}}
+	for _, s := range []*registry.ServiceInstance{a, b, other} {
+		if err := r.Register(ctx, s); err != nil {
+			t.Fatalf("注册失败: %v", err)
+		}
+	}
+	// 重复注册替换原有实例
+	a2 := &registry.ServiceInstance{ID: "1", Name: "above", Endpoints: []string{"thrift://127.0.0.1:9100"}}
+	if err := r.Register(ctx, a2); err != nil {
+		t.Fatalf("注册失败: %v", err)
+	}
+
+	services, err := r.GetService(ctx, "above")
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(services) != 2 || !services[0].Equal(b) || !services[1].Equal(a2) {
+		t.Fatalf("查询结果不符合预期: %v", services)
+	}
+
+	if err := r.Deregister(ctx, b); err != nil {
+		t.Fatalf("注销失败: %v", err)
+	}
+	services, err = r.GetService(ctx, "above")
+	if err != nil {
+		t.Fatalf("查询失败: %v", err)
+	}
+	if len(services) != 1 || services[0].ID != "1" {
+		t.Fatalf("注销后查询结果不符合预期: %v", services)
+	}
+}
+
+// TestRegistryWatch 测试 Watch 在首次存在实例与实例变化时返回
+func TestRegistryWatch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	r := New(filepath.Join(t.TempDir(), "registry.json"), WithWatchInterval(10*time.Millisecond))
+
+	w, err := r.Watch(ctx, "above")
+	if err != nil {
+		t.Fatalf("创建 watcher 失败: %v", err)
+	}
+	defer w.Stop()
+
+	s := &registry.ServiceInstance{ID: "1", Name: "above", Endpoints: []string{"thrift://127.0.0.1:9000"}}
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		r.Register(context.Background(), s)
+	}()
+	services, err := w.Next()
+	if err != nil {
+		t.Fatalf("等待注册失败: %v", err)
+	}
+	if len(services) != 1 || !services[0].Equal(s) {
+		t.Fatalf("watch 结果不符合预期: %v", services)
+	}
+
+	if err := r.Deregister(ctx, s); err != nil {
+		t.Fatalf("注销失败: %v", err)
+	}
+	services, err = w.Next()
+	if err != nil {
+		t.Fatalf("等待注销失败: %v", err)
+	}
+	if len(services) != 0 {
+		t.Fatalf("注销后 watch 结果不符合预期: %v", services)
+	}
+
+	w.Stop()
+	if _, err := w.Next(); err == nil {
+		t.Fatal("期望 Stop 后 Next 返回错误")
+	}
+}
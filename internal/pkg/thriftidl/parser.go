@@ -0,0 +1,630 @@
+package thriftidl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ParseFile 读取并解析 .thrift 文件
+func ParseFile(path string) (*Document, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(filepath.Base(path), src)
+}
+
+// Parse 解析 .thrift 文件内容，filename 只用于错误信息与 Document.Filename
+func Parse(filename string, src []byte) (*Document, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", filename, err)
+	}
+	p := &parser{toks: toks, doc: &Document{Filename: filename, Namespaces: map[string]string{}}}
+	if err := p.parseDocument(); err != nil {
+		return nil, fmt.Errorf("%s:%w", filename, err)
+	}
+	return p.doc, nil
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokInt
+	tokDouble
+	tokString
+	tokPunct
+)
+
+// token 词法单元，doc 为之前独占行的注释，trailing 为同一行之后的注释
+type token struct {
+	kind     tokenKind
+	text     string
+	line     int
+	doc      []string
+	trailing []string
+}
+
+func lex(src []byte) ([]token, error) {
+	var (
+		toks    []token
+		pending []string
+		line    = 1
+		s       = string(src)
+	)
+	// comment 同一行已有单元时作为尾随注释，否则作为下一个单元的前置注释
+	comment := func(text string, start int) {
+		if text == "" {
+			return
+		}
+		if n := len(toks); n > 0 && toks[n-1].line == start {
+			toks[n-1].trailing = append(toks[n-1].trailing, text)
+			return
+		}
+		pending = append(pending, text)
+	}
+	emit := func(kind tokenKind, text string) {
+		toks = append(toks, token{kind: kind, text: text, line: line, doc: pending})
+		pending = nil
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '#' || strings.HasPrefix(s[i:], "//"):
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				end = len(s) - i
+			}
+			text := strings.TrimLeft(s[i:i+end], "#/")
+			comment(strings.TrimSpace(text), line)
+			i += end
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("%d: 注释没有结束", line)
+			}
+			body := s[i+2 : i+2+end]
+			comment(cleanBlockComment(body), line)
+			line += strings.Count(body, "\n")
+			i += end + 4
+		case c == '"' || c == '\'':
+			j := i + 1
+			var b strings.Builder
+			for ; j < len(s) && s[j] != c; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+					switch s[j] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(s[j])
+					}
+					continue
+				}
+				if s[j] == '\n' {
+					line++
+				}
+				b.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("%d: 字符串没有结束", line)
+			}
+			emit(tokString, b.String())
+			i = j + 1
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && (isIdentStart(s[j]) || isDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			emit(tokIdent, s[i:j])
+			i = j
+		case isDigit(c) || ((c == '-' || c == '+') && i+1 < len(s) && isDigit(s[i+1])):
+			j := i + 1
+			kind := tokInt
+			if strings.HasPrefix(s[i:], "0x") || strings.HasPrefix(s[i:], "0X") {
+				j = i + 2
+				for j < len(s) && strings.IndexByte("0123456789abcdefABCDEF", s[j]) >= 0 {
+					j++
+				}
+			} else {
+				for j < len(s) && (isDigit(s[j]) || strings.IndexByte(".eE", s[j]) >= 0 ||
+					((s[j] == '-' || s[j] == '+') && (s[j-1] == 'e' || s[j-1] == 'E'))) {
+					if !isDigit(s[j]) {
+						kind = tokDouble
+					}
+					j++
+				}
+			}
+			emit(kind, s[i:j])
+			i = j
+		case strings.IndexByte("{}()<>,;:=[]*", c) >= 0:
+			emit(tokPunct, string(c))
+			i++
+		default:
+			return nil, fmt.Errorf("%d: 无法识别的字符 %q", line, c)
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, line: line, doc: pending})
+	return toks, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// cleanBlockComment 去掉块注释每行开头的 *
+func cleanBlockComment(body string) string {
+	body = strings.TrimPrefix(body, "*")
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(l), "*"))
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+type parser struct {
+	toks []token
+	pos  int
+	doc  *Document
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// prev 返回最近读取的单元，用于获取尾随注释
+func (p *parser) prev() token {
+	if p.pos == 0 {
+		return token{}
+	}
+	return p.toks[p.pos-1]
+}
+
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokPunct || t.kind == tokIdent) && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("期望 %q", text)
+	}
+	return nil
+}
+
+func (p *parser) ident() (string, error) {
+	t := p.peek()
+	if t.kind != tokIdent {
+		return "", p.errorf("期望标识符")
+	}
+	p.next()
+	return t.text, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	t := p.peek()
+	found := t.text
+	if t.kind == tokEOF {
+		found = "EOF"
+	}
+	return fmt.Errorf("%d: %s, 实际为 %q", t.line, fmt.Sprintf(format, args...), found)
+}
+
+// skipSeparator 跳过可选的 , 或 ;
+func (p *parser) skipSeparator() {
+	if !p.accept(",") {
+		p.accept(";")
+	}
+}
+
+// docOf 合并单元的前置注释与读取完毕后最后一个单元的尾随注释
+func docOf(first, last token) string {
+	parts := append(append([]string{}, first.doc...), last.trailing...)
+	return strings.Join(parts, "\n")
+}
+
+func (p *parser) parseDocument() error {
+	for p.peek().kind != tokEOF {
+		first := p.peek()
+		keyword, err := p.ident()
+		if err != nil {
+			return err
+		}
+		switch keyword {
+		case "namespace":
+			scope := "*"
+			if !p.accept("*") {
+				if scope, err = p.ident(); err != nil {
+					return err
+				}
+			}
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			p.doc.Namespaces[scope] = name
+			if _, err := p.annotations(); err != nil {
+				return err
+			}
+		case "include", "cpp_include":
+			t := p.next()
+			if t.kind != tokString {
+				return p.errorf("期望字符串")
+			}
+			if keyword == "include" {
+				p.doc.Includes = append(p.doc.Includes, t.text)
+			}
+		case "typedef":
+			typ, err := p.parseType()
+			if err != nil {
+				return err
+			}
+			name, err := p.ident()
+			if err != nil {
+				return err
+			}
+			if _, err := p.annotations(); err != nil {
+				return err
+			}
+			p.skipSeparator()
+			p.doc.Typedefs = append(p.doc.Typedefs, &Typedef{Name: name, Type: typ, Doc: docOf(first, p.prev())})
+		case "const":
+			if _, err := p.parseType(); err != nil {
+				return err
+			}
+			if _, err := p.ident(); err != nil {
+				return err
+			}
+			if err := p.expect("="); err != nil {
+				return err
+			}
+			if _, err := p.parseConst(); err != nil {
+				return err
+			}
+			p.skipSeparator()
+		case "enum":
+			e, err := p.parseEnum(first)
+			if err != nil {
+				return err
+			}
+			p.doc.Enums = append(p.doc.Enums, e)
+		case "struct", "union", "exception":
+			s, err := p.parseStruct(first, keyword)
+			if err != nil {
+				return err
+			}
+			p.doc.Structs = append(p.doc.Structs, s)
+		case "service":
+			s, err := p.parseService(first)
+			if err != nil {
+				return err
+			}
+			p.doc.Services = append(p.doc.Services, s)
+		default:
+			p.pos--
+			return p.errorf("不支持的定义")
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseEnum(first token) (*Enum, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	e := &Enum{Name: name, Doc: strings.Join(first.doc, "\n")}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	next := int64(0)
+	for !p.accept("}") {
+		vfirst := p.peek()
+		vname, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		v := &EnumValue{Name: vname, Value: next}
+		if p.accept("=") {
+			t := p.next()
+			if t.kind != tokInt {
+				return nil, p.errorf("期望整数")
+			}
+			if v.Value, err = strconv.ParseInt(t.text, 0, 64); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := p.annotations(); err != nil {
+			return nil, err
+		}
+		p.skipSeparator()
+		v.Doc = docOf(vfirst, p.prev())
+		next = v.Value + 1
+		e.Values = append(e.Values, v)
+	}
+	if _, err := p.annotations(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (p *parser) parseStruct(first token, kind string) (*Struct, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	p.accept("xsd_all")
+	s := &Struct{Kind: kind, Name: name, Doc: strings.Join(first.doc, "\n")}
+	if s.Fields, err = p.parseFields("{", "}"); err != nil {
+		return nil, err
+	}
+	if _, err := p.annotations(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *parser) parseService(first token) (*Service, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	s := &Service{Name: name, Doc: strings.Join(first.doc, "\n")}
+	if p.accept("extends") {
+		if s.Extends, err = p.ident(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	for !p.accept("}") {
+		f, err := p.parseFunction()
+		if err != nil {
+			return nil, err
+		}
+		s.Functions = append(s.Functions, f)
+	}
+	if _, err := p.annotations(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *parser) parseFunction() (*Function, error) {
+	first := p.peek()
+	f := &Function{}
+	f.Oneway = p.accept("oneway")
+	if !p.accept("void") {
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		f.Returns = typ
+	}
+	var err error
+	if f.Name, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if f.Args, err = p.parseFields("(", ")"); err != nil {
+		return nil, err
+	}
+	if p.accept("throws") {
+		if f.Throws, err = p.parseFields("(", ")"); err != nil {
+			return nil, err
+		}
+	}
+	if f.Annotations, err = p.annotations(); err != nil {
+		return nil, err
+	}
+	p.skipSeparator()
+	f.Doc = docOf(first, p.prev())
+	return f, nil
+}
+
+// parseFields 解析 open 与 close 之间的字段列表
+func (p *parser) parseFields(open, close string) ([]*Field, error) {
+	if err := p.expect(open); err != nil {
+		return nil, err
+	}
+	var fields []*Field
+	for !p.accept(close) {
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+func (p *parser) parseField() (*Field, error) {
+	first := p.peek()
+	f := &Field{}
+	if first.kind == tokInt {
+		p.next()
+		id, err := strconv.Atoi(first.text)
+		if err != nil {
+			return nil, err
+		}
+		f.ID = id
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("required") {
+		f.Requiredness = Required
+	} else if p.accept("optional") {
+		f.Requiredness = Optional
+	}
+	var err error
+	if f.Type, err = p.parseType(); err != nil {
+		return nil, err
+	}
+	if f.Name, err = p.ident(); err != nil {
+		return nil, err
+	}
+	if p.accept("=") {
+		if f.Default, err = p.parseConst(); err != nil {
+			return nil, err
+		}
+	}
+	if f.Annotations, err = p.annotations(); err != nil {
+		return nil, err
+	}
+	p.skipSeparator()
+	f.Doc = docOf(first, p.prev())
+	return f, nil
+}
+
+func (p *parser) parseType() (*Type, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	t := &Type{Name: name}
+	switch name {
+	case TypeList, TypeSet:
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		if t.Value, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+	case TypeMap:
+		if err := p.expect("<"); err != nil {
+			return nil, err
+		}
+		if t.Key, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+		if t.Value, err = p.parseType(); err != nil {
+			return nil, err
+		}
+		if err := p.expect(">"); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.annotations(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// annotations 解析可选的 (key = "value", ...) 注解
+func (p *parser) annotations() (map[string]string, error) {
+	if !p.is("(") || p.toks[p.pos+1].kind != tokIdent {
+		return nil, nil
+	}
+	p.next()
+	m := map[string]string{}
+	for !p.accept(")") {
+		key, err := p.ident()
+		if err != nil {
+			return nil, err
+		}
+		m[key] = "1"
+		if p.accept("=") {
+			t := p.next()
+			if t.kind != tokString {
+				return nil, p.errorf("注解 %s 的值必须是字符串", key)
+			}
+			m[key] = t.text
+		}
+		p.skipSeparator()
+	}
+	return m, nil
+}
+
+func (p *parser) parseConst() (*ConstValue, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokInt:
+		v, err := strconv.ParseInt(t.text, 0, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &ConstValue{Kind: ConstInt, Int: v}, nil
+	case t.kind == tokDouble:
+		v, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, err
+		}
+		return &ConstValue{Kind: ConstDouble, Double: v}, nil
+	case t.kind == tokString:
+		return &ConstValue{Kind: ConstString, String: t.text}, nil
+	case t.kind == tokIdent:
+		return &ConstValue{Kind: ConstIdentifier, String: t.text}, nil
+	case t.kind == tokPunct && t.text == "[":
+		v := &ConstValue{Kind: ConstList}
+		for !p.accept("]") {
+			item, err := p.parseConst()
+			if err != nil {
+				return nil, err
+			}
+			v.List = append(v.List, item)
+			p.skipSeparator()
+		}
+		return v, nil
+	case t.kind == tokPunct && t.text == "{":
+		v := &ConstValue{Kind: ConstMap}
+		for !p.accept("}") {
+			key, err := p.parseConst()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			value, err := p.parseConst()
+			if err != nil {
+				return nil, err
+			}
+			v.Map = append(v.Map, &ConstPair{Key: key, Value: value})
+			p.skipSeparator()
+		}
+		return v, nil
+	}
+	if t.kind != tokEOF {
+		p.pos--
+	}
+	return nil, p.errorf("期望常量")
+}
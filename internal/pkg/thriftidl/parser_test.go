@@ -0,0 +1,109 @@
+package thriftidl
+
+import (
+	"testing"
+)
+
+const testIDL = `
+namespace go demo
+include "shared.thrift"
+
+/** 状态 */
+enum Status {
+  ACTIVE = 1, // 启用
+  DISABLED,
+}
+
+typedef i64 UserId
+
+// 用户
+struct User {
+  1: required UserId id,   // 用户ID
+  2: optional string name = "guest",
+  3: list<string> tags = [],
+  4: map<string, i32> scores = {"a": 1},
+  5: Status status = Status.ACTIVE;
+}
+
+exception NotFound {
+  1: string message
+}
+
+service UserService extends shared.Base {
+  // 查询用户
+  User GetUser(1: UserId id) throws (1: NotFound notFound) (api.get = "/v1/users/{id}"),
+  oneway void Ping()
+}
+`
+
+func TestParse(t *testing.T) {
+	doc, err := Parse("demo.thrift", []byte(testIDL))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if ns := doc.Namespace("go"); ns != "demo" {
+		t.Fatalf("namespace 错误: %q", ns)
+	}
+	if len(doc.Includes) != 1 || doc.Includes[0] != "shared.thrift" {
+		t.Fatalf("include 错误: %v", doc.Includes)
+	}
+
+	e := doc.Enums[0]
+	if e.Doc != "状态" || len(e.Values) != 2 || e.Values[0].Doc != "启用" || e.Values[1].Value != 2 {
+		t.Fatalf("枚举解析错误: %+v %+v %+v", e, e.Values[0], e.Values[1])
+	}
+	if td := doc.Typedefs[0]; td.Name != "UserId" || td.Type.Name != TypeI64 {
+		t.Fatalf("typedef 解析错误: %+v", td)
+	}
+
+	s := doc.Structs[0]
+	if s.Doc != "用户" || len(s.Fields) != 5 {
+		t.Fatalf("结构体解析错误: %+v", s)
+	}
+	id := s.Fields[0]
+	if id.ID != 1 || id.Requiredness != Required || id.Type.Name != "UserId" || id.Doc != "用户ID" {
+		t.Fatalf("字段 id 解析错误: %+v", id)
+	}
+	if name := s.Fields[1]; name.Requiredness != Optional || name.Default.String != "guest" || name.Doc != "" {
+		t.Fatalf("字段 name 解析错误: %+v", name)
+	}
+	if tags := s.Fields[2]; tags.Type.String() != "list<string>" || tags.Default.Kind != ConstList {
+		t.Fatalf("字段 tags 解析错误: %+v", tags)
+	}
+	if scores := s.Fields[3]; scores.Type.String() != "map<string,i32>" || scores.Default.Map[0].Value.Int != 1 {
+		t.Fatalf("字段 scores 解析错误: %+v", scores)
+	}
+	if status := s.Fields[4]; status.Default.Kind != ConstIdentifier || status.Default.String != "Status.ACTIVE" {
+		t.Fatalf("字段 status 解析错误: %+v", status)
+	}
+	if ex := doc.Structs[1]; ex.Kind != "exception" || ex.Fields[0].Name != "message" {
+		t.Fatalf("异常解析错误: %+v", ex)
+	}
+
+	svc := doc.Services[0]
+	if svc.Extends != "shared.Base" || len(svc.Functions) != 2 {
+		t.Fatalf("服务解析错误: %+v", svc)
+	}
+	get := svc.Functions[0]
+	if get.Doc != "查询用户" || get.Returns.Name != "User" || len(get.Args) != 1 || len(get.Throws) != 1 ||
+		get.Annotations["api.get"] != "/v1/users/{id}" {
+		t.Fatalf("方法 GetUser 解析错误: %+v", get)
+	}
+	if ping := svc.Functions[1]; !ping.Oneway || ping.Returns != nil {
+		t.Fatalf("方法 Ping 解析错误: %+v", ping)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, src := range []string{
+		"struct A { 1: i32 }",
+		"enum E { A = x }",
+		"service S { void f( }",
+		"/* 未结束",
+		"senum S {}",
+	} {
+		if _, err := Parse("bad.thrift", []byte(src)); err == nil {
+			t.Errorf("期望 %q 解析失败", src)
+		}
+	}
+}
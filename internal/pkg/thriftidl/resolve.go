@@ -0,0 +1,111 @@
+package thriftidl
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Set 一组 .thrift 文件，引用其他文件的类型时按文件名（不含扩展名）查找
+type Set struct {
+	Docs   []*Document
+	byName map[string]*Document
+}
+
+// ParseFiles 解析多个 .thrift 文件
+func ParseFiles(paths ...string) (*Set, error) {
+	var docs []*Document
+	for _, path := range paths {
+		doc, err := ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	return NewSet(docs...), nil
+}
+
+// NewSet 由已解析的文件创建 Set
+func NewSet(docs ...*Document) *Set {
+	s := &Set{Docs: docs, byName: map[string]*Document{}}
+	for _, doc := range docs {
+		s.byName[doc.BaseName()] = doc
+	}
+	return s
+}
+
+// BaseName 不含扩展名的文件名
+func (d *Document) BaseName() string {
+	return strings.TrimSuffix(d.Filename, filepath.Ext(d.Filename))
+}
+
+// Resolved 展开 typedef 之后的类型，Enum、Struct 非空时为用户定义类型，Doc 为定义所在的文件
+type Resolved struct {
+	Doc    *Document
+	Name   string
+	Type   *Type
+	Enum   *Enum
+	Struct *Struct
+}
+
+// IsScalar 是否为除 binary 外的基础类型或枚举
+func (r *Resolved) IsScalar() bool {
+	if r.Enum != nil {
+		return true
+	}
+	return r.Struct == nil && r.Type.IsBase() && r.Type.Name != TypeBinary
+}
+
+// Resolve 解析 doc 中引用的类型，typedef 一直展开到基础类型、容器或用户定义类型
+func (s *Set) Resolve(doc *Document, t *Type) (*Resolved, error) {
+	if t.IsBase() || t.IsContainer() {
+		return &Resolved{Doc: doc, Type: t}, nil
+	}
+	name := t.Name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		inc, ok := s.byName[name[:i]]
+		if !ok {
+			return nil, fmt.Errorf("%s: 找不到 %s 所在的文件", doc.Filename, name)
+		}
+		doc, name = inc, name[i+1:]
+	}
+	for _, e := range doc.Enums {
+		if e.Name == name {
+			return &Resolved{Doc: doc, Name: name, Type: t, Enum: e}, nil
+		}
+	}
+	for _, st := range doc.Structs {
+		if st.Name == name {
+			return &Resolved{Doc: doc, Name: name, Type: t, Struct: st}, nil
+		}
+	}
+	for _, td := range doc.Typedefs {
+		if td.Name == name {
+			return s.Resolve(doc, td.Type)
+		}
+	}
+	return nil, fmt.Errorf("%s: 未定义的类型 %s", doc.Filename, t.Name)
+}
+
+// commonInitialisms 与 thrift go 生成器一致，整个名字是缩写时全部大写
+var commonInitialisms = map[string]bool{
+	"API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true,
+	"QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SSH": true,
+	"TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true,
+	"URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true, "XSRF": true, "XSS": true,
+}
+
+// GoName 按 thrift go 生成器的规则把字段、方法名转换为导出的 Go 名字
+func GoName(name string) string {
+	if commonInitialisms[strings.ToUpper(name)] {
+		return strings.ToUpper(name)
+	}
+	parts := strings.Split(name, "_")
+	for i, p := range parts {
+		if p != "" && (i == 0 || p[0] >= 'a' && p[0] <= 'z') {
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
@@ -0,0 +1,50 @@
+package thriftidl
+
+import (
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	shared, err := Parse("shared.thrift", []byte(`struct Base { 1: i64 id }`))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	doc, err := Parse("demo.thrift", []byte(testIDL))
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	set := NewSet(doc, shared)
+
+	user := doc.Structs[0]
+	ref, err := set.Resolve(doc, user.Fields[0].Type)
+	if err != nil || ref.Type.Name != TypeI64 || !ref.IsScalar() {
+		t.Fatalf("typedef 应展开为 i64: %+v, %v", ref, err)
+	}
+	ref, err = set.Resolve(doc, user.Fields[4].Type)
+	if err != nil || ref.Enum == nil || ref.Name != "Status" || !ref.IsScalar() {
+		t.Fatalf("应解析为枚举 Status: %+v, %v", ref, err)
+	}
+	ref, err = set.Resolve(doc, &Type{Name: "shared.Base"})
+	if err != nil || ref.Struct == nil || ref.Doc != shared || ref.IsScalar() {
+		t.Fatalf("应解析为 shared.thrift 中的结构体: %+v, %v", ref, err)
+	}
+	for _, name := range []string{"Missing", "other.Base"} {
+		if _, err := set.Resolve(doc, &Type{Name: name}); err == nil {
+			t.Errorf("期望 %s 解析失败", name)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for name, want := range map[string]string{
+		"id":         "ID",
+		"senderId":   "SenderId",
+		"echoData":   "EchoData",
+		"created_at": "CreatedAt",
+		"url":        "URL",
+	} {
+		if got := GoName(name); got != want {
+			t.Errorf("GoName(%q) = %q, 期望 %q", name, got, want)
+		}
+	}
+}
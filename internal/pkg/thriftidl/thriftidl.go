@@ -0,0 +1,172 @@
+// Package thriftidl 解析 .thrift 接口定义，供代码生成使用
+//
+// 只保留生成 HTTP 网关、OpenAPI 文档与 .proto 文件需要的信息：类型、字段、注释与注解，
+// 常量与 typedef 之外的语义检查交给 thrift 编译器。
+package thriftidl
+
+// 基础类型名
+const (
+	TypeBool   = "bool"
+	TypeByte   = "byte"
+	TypeI8     = "i8"
+	TypeI16    = "i16"
+	TypeI32    = "i32"
+	TypeI64    = "i64"
+	TypeDouble = "double"
+	TypeString = "string"
+	TypeBinary = "binary"
+	TypeList   = "list"
+	TypeSet    = "set"
+	TypeMap    = "map"
+)
+
+// Document 一个 .thrift 文件
+type Document struct {
+	Filename   string
+	Namespaces map[string]string
+	Includes   []string
+	Typedefs   []*Typedef
+	Enums      []*Enum
+	Structs    []*Struct
+	Services   []*Service
+}
+
+// Namespace 返回指定语言的命名空间，未声明时回退到 * 的声明
+func (d *Document) Namespace(lang string) string {
+	if ns, ok := d.Namespaces[lang]; ok {
+		return ns
+	}
+	return d.Namespaces["*"]
+}
+
+// Type 字段或返回值的类型
+//
+// 基础类型与用户定义类型只有 Name；list、set 的元素类型在 Value 中，map 的键、值类型在 Key、Value 中。
+type Type struct {
+	Name  string
+	Key   *Type
+	Value *Type
+}
+
+// IsBase 是否为基础类型
+func (t *Type) IsBase() bool {
+	switch t.Name {
+	case TypeBool, TypeByte, TypeI8, TypeI16, TypeI32, TypeI64, TypeDouble, TypeString, TypeBinary:
+		return true
+	}
+	return false
+}
+
+// IsContainer 是否为 list、set 或 map
+func (t *Type) IsContainer() bool {
+	return t.Name == TypeList || t.Name == TypeSet || t.Name == TypeMap
+}
+
+func (t *Type) String() string {
+	switch t.Name {
+	case TypeList, TypeSet:
+		return t.Name + "<" + t.Value.String() + ">"
+	case TypeMap:
+		return "map<" + t.Key.String() + "," + t.Value.String() + ">"
+	}
+	return t.Name
+}
+
+// Requiredness 字段的必填属性
+type Requiredness int
+
+const (
+	// Default 未声明 required 或 optional
+	Default Requiredness = iota
+	Required
+	Optional
+)
+
+// Field 结构体字段、方法参数或异常声明
+type Field struct {
+	ID           int
+	Name         string
+	Type         *Type
+	Requiredness Requiredness
+	Default      *ConstValue
+	Doc          string
+	Annotations  map[string]string
+}
+
+// Typedef 类型别名
+type Typedef struct {
+	Name string
+	Type *Type
+	Doc  string
+}
+
+// Enum 枚举
+type Enum struct {
+	Name   string
+	Values []*EnumValue
+	Doc    string
+}
+
+// EnumValue 枚举值
+type EnumValue struct {
+	Name  string
+	Value int64
+	Doc   string
+}
+
+// Struct struct、union 或 exception
+type Struct struct {
+	Kind   string
+	Name   string
+	Fields []*Field
+	Doc    string
+}
+
+// Service 服务
+type Service struct {
+	Name      string
+	Extends   string
+	Functions []*Function
+	Doc       string
+}
+
+// Function 服务方法，Returns 为 nil 表示 void
+type Function struct {
+	Name        string
+	Oneway      bool
+	Returns     *Type
+	Args        []*Field
+	Throws      []*Field
+	Doc         string
+	Annotations map[string]string
+}
+
+// ConstKind 常量值的种类
+type ConstKind int
+
+const (
+	ConstInt ConstKind = iota
+	ConstDouble
+	ConstString
+	ConstIdentifier
+	ConstList
+	ConstMap
+)
+
+// ConstValue 字段默认值或常量
+//
+// ConstString 与 ConstIdentifier 的值在 String 中，ConstMap 的键值对在 Map 中。
+type ConstValue struct {
+	Kind   ConstKind
+	Int    int64
+	Double float64
+	String string
+	List   []*ConstValue
+	Map    []*ConstPair
+}
+
+// ConstPair map 常量的一个键值对
+type ConstPair struct {
+	Key   *ConstValue
+	Value *ConstValue
+}
@@ -0,0 +1,194 @@
+// Package thrifttest 提供 thrift 服务端、客户端测试共用的服务实现与启动函数，只在测试中使用
+package thrifttest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// UserService 测试用的用户服务，原样返回 clientData；clientData 为 slow 时延迟 300ms 返回，为 panic 时触发 panic
+type UserService struct{}
+
+func (s *UserService) EchoData(ctx context.Context, clientData []byte, user *user_service.User) (*user_service.EchoResponse, error) {
+	switch string(clientData) {
+	case "slow":
+		time.Sleep(300 * time.Millisecond)
+	case "panic":
+		panic("echo panic")
+	}
+	return &user_service.EchoResponse{ServerId: 1, ClientData: clientData, User: user}, nil
+}
+
+// GiftService 测试用的礼物服务
+type GiftService struct{}
+
+func (s *GiftService) SendGift(ctx context.Context, senderId int64, receiverId int64, price int32, giftType gift_service.GiftType, quantity int32) (*gift_service.Gift, error) {
+	return &gift_service.Gift{
+		GiftId:     100,
+		SenderId:   senderId,
+		ReceiverId: receiverId,
+		Price:      price,
+		GiftType:   giftType,
+		Quantity:   quantity,
+	}, nil
+}
+
+func (s *GiftService) GetTop10Senders(ctx context.Context) ([]int64, error) {
+	return []int64{1, 2, 3}, nil
+}
+
+func (s *GiftService) GetSendersInLastWeek(ctx context.Context) ([]int64, error) {
+	return []int64{1}, nil
+}
+
+func (s *GiftService) GetGiftsBySender(ctx context.Context, senderId int64) ([]*gift_service.Gift, error) {
+	return []*gift_service.Gift{{GiftId: 100, SenderId: senderId}}, nil
+}
+
+// IdempotentGiftService 模拟按请求头 idempotency-key 对 SendGift 去重的服务端，相同幂等键返回第一次创建的礼物，
+// 用于测试客户端重试时发送相同的幂等键
+type IdempotentGiftService struct {
+	GiftService
+
+	mu     sync.Mutex
+	nextID int64
+	gifts  map[string]*gift_service.Gift
+	sends  map[string]int
+}
+
+func (s *IdempotentGiftService) SendGift(ctx context.Context, senderId int64, receiverId int64, price int32, giftType gift_service.GiftType, quantity int32) (*gift_service.Gift, error) {
+	var key string
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		key = tr.RequestHeader().Get(thriftx.IdempotencyKeyHeader)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gift, ok := s.gifts[key]; ok && key != "" {
+		return gift, nil
+	}
+	if s.gifts == nil {
+		s.gifts = make(map[string]*gift_service.Gift)
+		s.sends = make(map[string]int)
+	}
+	s.nextID++
+	gift := &gift_service.Gift{
+		GiftId:     s.nextID,
+		SenderId:   senderId,
+		ReceiverId: receiverId,
+		Price:      price,
+		GiftType:   giftType,
+		Quantity:   quantity,
+	}
+	s.sends[key]++
+	if key != "" {
+		s.gifts[key] = gift
+	}
+	return gift, nil
+}
+
+// Sends 返回幂等键 key 实际创建礼物的次数，key 为空时为没有幂等键的调用次数
+func (s *IdempotentGiftService) Sends(key string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sends[key]
+}
+
+// FailingGiftService SendGift 与 GetGiftsBySender 执行后返回 Err 的礼物服务，记录各方法的执行次数
+type FailingGiftService struct {
+	GiftService
+	Err error
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (s *FailingGiftService) SendGift(ctx context.Context, senderId int64, receiverId int64, price int32, giftType gift_service.GiftType, quantity int32) (*gift_service.Gift, error) {
+	s.record("SendGift")
+	return nil, s.Err
+}
+
+func (s *FailingGiftService) GetGiftsBySender(ctx context.Context, senderId int64) ([]*gift_service.Gift, error) {
+	s.record("GetGiftsBySender")
+	return nil, s.Err
+}
+
+func (s *FailingGiftService) record(method string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls == nil {
+		s.calls = make(map[string]int)
+	}
+	s.calls[method]++
+}
+
+// Calls 返回 method 的执行次数
+func (s *FailingGiftService) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// FreeAddr 获取一个空闲的本地端口
+func FreeAddr(t testing.TB) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("获取空闲端口失败: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// Serve 启动 srv 并等待 network、addr 可以连接，network 为空时为 tcp，stop 停止服务端并返回 Start 的错误
+func Serve(srv transport.Server, network, addr string) (stop func() error, err error) {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Start(context.Background())
+	}()
+	stop = func() error {
+		srv.Stop(context.Background())
+		return <-errc
+	}
+	if network == "" {
+		network = "tcp"
+	}
+	for i := 0; i < 50; i++ {
+		select {
+		case err := <-errc:
+			return nil, fmt.Errorf("启动服务端失败: %w", err)
+		default:
+		}
+		conn, err := net.Dial(network, addr)
+		if err == nil {
+			conn.Close()
+			return stop, nil
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	stop()
+	return nil, fmt.Errorf("服务端未在 %s 上监听", addr)
+}
+
+// Start 同 Serve，测试结束时停止服务端
+func Start(t testing.TB, srv transport.Server, network, addr string) {
+	t.Helper()
+	stop, err := Serve(srv, network, addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := stop(); err != nil {
+			t.Errorf("服务端运行失败: %v", err)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package thriftx
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+)
+
+// NewEndpoint 根据监听地址生成 thrift://host:port，启用 TLS 时带上 isSecure=true
+//
+// 监听在 0.0.0.0、:: 或未指定 host 时使用本机第一个可用的网卡地址，便于注册到服务发现。
+// unix socket 生成 thrift+unix:///path/to/socket。
+func NewEndpoint(network, addr string, secure bool) (*url.URL, error) {
+	var u *url.URL
+	if network == NetworkUnix {
+		u = &url.URL{Scheme: "thrift+unix", Path: addr}
+	} else {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("解析监听地址 %s 失败: %w", addr, err)
+		}
+		if host, err = endpointHost(host); err != nil {
+			return nil, err
+		}
+		u = &url.URL{Scheme: "thrift", Host: net.JoinHostPort(host, port)}
+	}
+	if secure {
+		u.RawQuery = url.Values{"isSecure": {strconv.FormatBool(secure)}}.Encode()
+	}
+	return u, nil
+}
+
+// endpointHost 监听在全部地址上时换成本机地址
+func endpointHost(host string) (string, error) {
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return localIP()
+	}
+	return host, nil
+}
+
+// localIP 返回本机第一个已启用网卡上的非回环地址，优先 IPv4，找不到时返回 127.0.0.1
+func localIP() (string, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return "", fmt.Errorf("获取网卡失败: %w", err)
+	}
+	var ipv6 net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || !ipNet.IP.IsGlobalUnicast() {
+				continue
+			}
+			if ip := ipNet.IP.To4(); ip != nil {
+				return ip.String(), nil
+			}
+			if ipv6 == nil {
+				ipv6 = ipNet.IP
+			}
+		}
+	}
+	if ipv6 != nil {
+		return ipv6.String(), nil
+	}
+	return "127.0.0.1", nil
+}
@@ -0,0 +1,134 @@
+package thriftx
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/errors"
+)
+
+// applicationErrors TApplicationException 类型对应的 kratos 错误
+var applicationErrors = map[int32]func(reason, message string) *errors.Error{
+	thrift.UNKNOWN_METHOD:                 errors.NotFound,
+	thrift.INVALID_MESSAGE_TYPE_EXCEPTION: errors.BadRequest,
+	thrift.WRONG_METHOD_NAME:              errors.BadRequest,
+	thrift.BAD_SEQUENCE_ID:                errors.BadRequest,
+	thrift.PROTOCOL_ERROR:                 errors.BadRequest,
+	thrift.INVALID_TRANSFORM:              errors.BadRequest,
+	thrift.INVALID_PROTOCOL:               errors.BadRequest,
+	thrift.UNSUPPORTED_CLIENT_TYPE:        errors.BadRequest,
+	LOADSHEDDING:                          errors.ServiceUnavailable,
+	TIMEOUT:                               errors.GatewayTimeout,
+	REQUEST_TOO_LARGE: func(reason, message string) *errors.Error {
+		return errors.New(http.StatusRequestEntityTooLarge, reason, message)
+	},
+	UNAUTHORIZED: errors.Unauthorized,
+	FORBIDDEN:    errors.Forbidden,
+	NOT_FOUND:    errors.NotFound,
+}
+
+var applicationReasons = map[int32]string{
+	thrift.UNKNOWN_APPLICATION_EXCEPTION:  "UNKNOWN",
+	thrift.UNKNOWN_METHOD:                 "UNKNOWN_METHOD",
+	thrift.INVALID_MESSAGE_TYPE_EXCEPTION: "INVALID_MESSAGE_TYPE",
+	thrift.WRONG_METHOD_NAME:              "WRONG_METHOD_NAME",
+	thrift.BAD_SEQUENCE_ID:                "BAD_SEQUENCE_ID",
+	thrift.MISSING_RESULT:                 "MISSING_RESULT",
+	thrift.INTERNAL_ERROR:                 "INTERNAL_ERROR",
+	thrift.PROTOCOL_ERROR:                 "PROTOCOL_ERROR",
+	thrift.INVALID_TRANSFORM:              "INVALID_TRANSFORM",
+	thrift.INVALID_PROTOCOL:               "INVALID_PROTOCOL",
+	thrift.UNSUPPORTED_CLIENT_TYPE:        "UNSUPPORTED_CLIENT_TYPE",
+	LOADSHEDDING:                          "LOADSHEDDING",
+	TIMEOUT:                               "TIMEOUT",
+	REQUEST_TOO_LARGE:                     "REQUEST_TOO_LARGE",
+	UNAUTHORIZED:                          "UNAUTHORIZED",
+	FORBIDDEN:                             "FORBIDDEN",
+	NOT_FOUND:                             "NOT_FOUND",
+}
+
+// codeApplicationTypes kratos 错误码对应的 TApplicationException 类型，其余 4xx 为 PROTOCOL_ERROR
+var codeApplicationTypes = map[int32]int32{
+	http.StatusUnauthorized:          UNAUTHORIZED,
+	http.StatusForbidden:             FORBIDDEN,
+	http.StatusNotFound:              NOT_FOUND,
+	http.StatusRequestEntityTooLarge: REQUEST_TOO_LARGE,
+	http.StatusTooManyRequests:       LOADSHEDDING,
+	http.StatusServiceUnavailable:    LOADSHEDDING,
+	http.StatusGatewayTimeout:        TIMEOUT,
+}
+
+// FromError 把 thrift 异常与 ctx 错误转换为带 HTTP 状态码的 kratos 错误，其余错误原样返回
+//
+// IDL 中声明的异常属于业务错误，映射为 400，reason 为异常的类型名。
+func FromError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var se *errors.Error
+	if stderrors.As(err, &se) {
+		return err
+	}
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.GatewayTimeout("TIMEOUT", err.Error()).WithCause(err)
+	case stderrors.Is(err, context.Canceled):
+		return errors.ClientClosed("CANCELED", err.Error()).WithCause(err)
+	}
+	var te thrift.TException
+	if !stderrors.As(err, &te) {
+		return err
+	}
+	switch te.TExceptionType() {
+	case thrift.TExceptionTypeApplication:
+		var ae thrift.TApplicationException
+		if !stderrors.As(err, &ae) {
+			return err
+		}
+		reason, ok := applicationReasons[ae.TypeId()]
+		if !ok {
+			reason = "UNKNOWN"
+		}
+		newError, ok := applicationErrors[ae.TypeId()]
+		if !ok {
+			newError = errors.InternalServer
+		}
+		return newError(reason, err.Error()).WithCause(err)
+	case thrift.TExceptionTypeProtocol:
+		return errors.BadRequest("PROTOCOL_ERROR", err.Error()).WithCause(err)
+	case thrift.TExceptionTypeTransport:
+		return errors.ServiceUnavailable("TRANSPORT_ERROR", err.Error()).WithCause(err)
+	case thrift.TExceptionTypeCompiled:
+		t := reflect.TypeOf(te)
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		return errors.BadRequest(t.Name(), err.Error()).WithCause(err)
+	}
+	return err
+}
+
+// ToApplicationException 把 kratos 错误与 ctx 错误转换为对应类型的 TApplicationException，是 FromError 的逆向转换
+//
+// 限流、熔断返回的 429、503 为 LOADSHEDDING，客户端据此判断请求没有被处理，因此只能用于处理函数执行之前的错误，
+// 处理函数执行之后的 429、503 由调用方按 INTERNAL_ERROR 处理；504 为 TIMEOUT；
+// 4xx 为对应的请求错误。5xx 与其他错误没有对应的类型，返回 false，由调用方按 INTERNAL_ERROR 处理。
+func ToApplicationException(err error) (thrift.TApplicationException, bool) {
+	var se *errors.Error
+	if !stderrors.As(FromError(err), &se) {
+		return nil, false
+	}
+	typ, ok := codeApplicationTypes[se.Code]
+	if !ok {
+		// 499 为 ctx 取消，请求可能已经处理了一部分，与 5xx 一样没有对应的类型
+		if se.Code < http.StatusBadRequest || se.Code >= 499 {
+			return nil, false
+		}
+		typ = thrift.PROTOCOL_ERROR
+	}
+	return thrift.NewTApplicationException(typ, fmt.Sprintf("%s: %s", se.Reason, se.Message)), true
+}
@@ -0,0 +1,32 @@
+package thriftx
+
+import "github.com/apache/thrift/lib/go/thrift"
+
+// apache thrift 未定义的 TApplicationException 类型，取值与 fbthrift 保持一致
+const (
+	// LOADSHEDDING 服务端过载，请求被拒绝
+	LOADSHEDDING = 11
+	// TIMEOUT 请求处理超时
+	TIMEOUT = 12
+	// REQUEST_TOO_LARGE 请求超出方法的大小上限，fbthrift 中没有对应的类型
+	REQUEST_TOO_LARGE = 100
+	// UNAUTHORIZED、FORBIDDEN、NOT_FOUND 对应中间件或处理函数返回的 401、403、404 kratos 错误，fbthrift 中没有对应的类型
+	UNAUTHORIZED = 101
+	FORBIDDEN    = 102
+	NOT_FOUND    = 103
+)
+
+// NewLoadSheddingException 创建服务端过载异常
+func NewLoadSheddingException(message string) thrift.TApplicationException {
+	return thrift.NewTApplicationException(LOADSHEDDING, message)
+}
+
+// NewTimeoutException 创建请求处理超时异常
+func NewTimeoutException(message string) thrift.TApplicationException {
+	return thrift.NewTApplicationException(TIMEOUT, message)
+}
+
+// NewRequestTooLargeException 创建请求过大异常
+func NewRequestTooLargeException(message string) thrift.TApplicationException {
+	return thrift.NewTApplicationException(REQUEST_TOO_LARGE, message)
+}
@@ -0,0 +1,60 @@
+package thriftx
+
+import (
+	"strings"
+
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+// KindThrift thrift 的 transport 类型，用于与 http、grpc 请求区分
+const KindThrift transport.Kind = "thrift"
+
+// IdempotencyKeyHeader 幂等键的请求头，thrift 只有 THeader 传输层能传递
+const IdempotencyKeyHeader = "idempotency-key"
+
+// Operation 由服务名与方法名组成 transport 的 operation，例如 GiftService.SendGift
+func Operation(service, method string) string {
+	return service + "." + method
+}
+
+// HeaderCarrier 基于 THeader 头的 transport.Header 实现
+//
+// THeader 的每个 key 只有一个值，Add 时多个值以逗号拼接。
+type HeaderCarrier map[string]string
+
+// Get returns the value associated with the passed key.
+func (hc HeaderCarrier) Get(key string) string {
+	return hc[key]
+}
+
+// Set stores the key-value pair.
+func (hc HeaderCarrier) Set(key string, value string) {
+	hc[key] = value
+}
+
+// Add append value to key-values pair.
+func (hc HeaderCarrier) Add(key string, value string) {
+	if v, ok := hc[key]; ok && v != "" {
+		hc[key] = v + "," + value
+		return
+	}
+	hc[key] = value
+}
+
+// Keys lists the keys stored in this carrier.
+func (hc HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(hc))
+	for k := range hc {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns a slice of values associated with the passed key.
+func (hc HeaderCarrier) Values(key string) []string {
+	v, ok := hc[key]
+	if !ok {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
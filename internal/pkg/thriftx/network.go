@@ -0,0 +1,39 @@
+package thriftx
+
+import "fmt"
+
+// 支持的网络类型
+const (
+	NetworkTCP  = "tcp"
+	NetworkTCP4 = "tcp4"
+	NetworkTCP6 = "tcp6"
+	NetworkUnix = "unix"
+)
+
+// Addr 带网络类型的地址，thrift 自带的地址只支持 tcp
+type Addr struct {
+	Net     string
+	Address string
+}
+
+// Network returns name of the network.
+func (a Addr) Network() string {
+	return a.Net
+}
+
+// String returns string form of address.
+func (a Addr) String() string {
+	return a.Address
+}
+
+// NewAddr 创建指定网络类型的地址，network 为空时使用 tcp，unix 时 address 为 socket 文件路径
+func NewAddr(network, address string) (Addr, error) {
+	switch network {
+	case "":
+		network = NetworkTCP
+	case NetworkTCP, NetworkTCP4, NetworkTCP6, NetworkUnix:
+	default:
+		return Addr{}, fmt.Errorf("unsupported thrift network: %q", network)
+	}
+	return Addr{Net: network, Address: address}, nil
+}
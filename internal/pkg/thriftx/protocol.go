@@ -0,0 +1,36 @@
+package thriftx
+
+import (
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// 支持的协议名称
+const (
+	ProtocolBinary  = "binary"
+	ProtocolCompact = "compact"
+	ProtocolJSON    = "json"
+	ProtocolHeader  = "header"
+	// ProtocolAuto 仅用于服务端，按连接自动识别 binary、compact 以及 header 帧格式
+	ProtocolAuto = "auto"
+)
+
+// NewProtocolFactory 根据协议名称创建协议工厂，名称为空时使用 binary
+func NewProtocolFactory(name string, cfg *thrift.TConfiguration) (thrift.TProtocolFactory, error) {
+	switch name {
+	case "", ProtocolBinary:
+		return thrift.NewTBinaryProtocolFactoryConf(cfg), nil
+	case ProtocolCompact:
+		return thrift.NewTCompactProtocolFactoryConf(cfg), nil
+	case ProtocolJSON:
+		return thrift.NewTJSONProtocolFactory(), nil
+	case ProtocolHeader, ProtocolAuto:
+		// THeader 服务端会先探测前 4 个字节，非 header 的 binary/compact 请求（无论是否分帧）
+		// 都会按客户端原本的格式解析和响应，所以 auto 与 header 在服务端是同一个工厂。
+		// 注意 binary 客户端需要使用严格模式写入，否则无法被识别
+		return thrift.NewTHeaderProtocolFactoryConf(cfg), nil
+	default:
+		return nil, fmt.Errorf("unsupported thrift protocol: %q", name)
+	}
+}
@@ -0,0 +1,55 @@
+package thriftx
+
+import (
+	"fmt"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// 支持的传输层名称
+const (
+	TransportBuffered = "buffered"
+	TransportFramed   = "framed"
+	TransportHeader   = "header"
+)
+
+// bufferSize 缓冲传输层的缓冲区大小
+const bufferSize = 2048
+
+// NewFactories 根据协议与传输层名称创建成对的工厂，服务端与客户端共用同一套规则：
+// 传输层为 header 时使用 THeader 协议，内层编码由 protocol 决定（binary 或 compact）；
+// 协议为 header 或 auto 时分帧由 THeader 自身处理，传输层只能为空或 header。
+func NewFactories(protocol, transport string, cfg *thrift.TConfiguration) (thrift.TTransportFactory, thrift.TProtocolFactory, error) {
+	if transport == TransportHeader || protocol == ProtocolHeader || protocol == ProtocolAuto {
+		if transport != "" && transport != TransportHeader {
+			return nil, nil, fmt.Errorf("thrift protocol %q can not be used with transport %q", protocol, transport)
+		}
+		headerCfg := &thrift.TConfiguration{}
+		if cfg != nil {
+			*headerCfg = *cfg
+		}
+		switch protocol {
+		case "", ProtocolBinary, ProtocolHeader, ProtocolAuto:
+			headerCfg.THeaderProtocolID = thrift.THeaderProtocolIDPtrMust(thrift.THeaderProtocolBinary)
+		case ProtocolCompact:
+			headerCfg.THeaderProtocolID = thrift.THeaderProtocolIDPtrMust(thrift.THeaderProtocolCompact)
+		default:
+			return nil, nil, fmt.Errorf("thrift protocol %q can not be used with transport %q", protocol, TransportHeader)
+		}
+		// THeader 协议会自行包装 THeaderTransport
+		return thrift.NewTTransportFactory(), thrift.NewTHeaderProtocolFactoryConf(headerCfg), nil
+	}
+
+	protocolFactory, err := NewProtocolFactory(protocol, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch transport {
+	case "", TransportBuffered:
+		return thrift.NewTBufferedTransportFactory(bufferSize), protocolFactory, nil
+	case TransportFramed:
+		return thrift.NewTFramedTransportFactoryConf(thrift.NewTTransportFactory(), cfg), protocolFactory, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported thrift transport: %q", transport)
+	}
+}
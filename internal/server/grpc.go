@@ -1,19 +1,25 @@
 package server
 
 import (
+	"context"
+
+	giftv1 "aboveThriftRPC/api/gift_service/v1"
+	userv1 "aboveThriftRPC/api/user_service/v1"
 	"aboveThriftRPC/internal/conf"
-	"aboveThriftRPC/internal/service"
+	"aboveThriftRPC/internal/pkg/thriftx"
 
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
 	"github.com/go-kratos/kratos/v2/transport/grpc"
 )
 
 // NewGRPCServer new a gRPC server.
-func NewGRPCServer(c *conf.Server, greeter *service.UserService, logger log.Logger) *grpc.Server {
+func NewGRPCServer(c *conf.Server, user userv1.UserServiceServer, gift giftv1.GiftServiceServer, logger log.Logger) *grpc.Server {
 	var opts = []grpc.ServerOption{
 		grpc.Middleware(
 			recovery.Recovery(),
+			thriftErrors(),
 		),
 	}
 	if c.Grpc.Network != "" {
@@ -26,6 +32,20 @@ func NewGRPCServer(c *conf.Server, greeter *service.UserService, logger log.Logg
 		opts = append(opts, grpc.Timeout(c.Grpc.Timeout.AsDuration()))
 	}
 	srv := grpc.NewServer(opts...)
-	// todo:生成grpcpb文件后注册
+	userv1.RegisterUserServiceServer(srv, user)
+	giftv1.RegisterGiftServiceServer(srv, gift)
 	return srv
 }
+
+// thriftErrors 把 thrift 异常映射为 kratos 错误，再由 kratos 转换为对应的 gRPC 状态码
+func thriftErrors() middleware.Middleware {
+	return func(handler middleware.Handler) middleware.Handler {
+		return func(ctx context.Context, req interface{}) (interface{}, error) {
+			reply, err := handler(ctx, req)
+			if err != nil {
+				return nil, thriftx.FromError(err)
+			}
+			return reply, nil
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	giftv1 "aboveThriftRPC/api/gift_service/v1"
+	userv1 "aboveThriftRPC/api/user_service/v1"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/service"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/transport/grpc"
+	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// startTestGRPCServer 使用指定的礼物服务启动 gRPC 服务端，返回客户端连接
+func startTestGRPCServer(t *testing.T, gift gift_service.GiftService) *ggrpc.ClientConn {
+	t.Helper()
+	c := &conf.Server{Grpc: &conf.Server_GRPC{Addr: thrifttest.FreeAddr(t)}}
+	srv := NewGRPCServer(c, service.NewGRPCUserService(&thrifttest.UserService{}), service.NewGRPCGiftService(gift), log.DefaultLogger)
+	thrifttest.Start(t, srv, "tcp", c.Grpc.Addr)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, err := grpc.DialInsecure(ctx, grpc.WithEndpoint(c.Grpc.Addr))
+	if err != nil {
+		t.Fatalf("连接 gRPC 服务端失败: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestGRPCServer 测试 gRPC 请求经过类型转换后调用 thrift 实现
+func TestGRPCServer(t *testing.T) {
+	conn := startTestGRPCServer(t, &thrifttest.GiftService{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user := &userv1.User{
+		Id:       7,
+		Name:     "test",
+		Details:  &userv1.UserDetails{Phone: proto.String("123"), Metadata: map[string]string{"k": "v"}},
+		Tags:     []string{"a"},
+		IsActive: proto.Bool(true),
+		Avatar:   []byte{1, 2},
+	}
+	echo, err := userv1.NewUserServiceClient(conn).EchoData(ctx, &userv1.EchoDataRequest{ClientData: []byte("hello"), User: user})
+	if err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	// 未设置的枚举使用 IDL 中的默认值 USER
+	want := proto.Clone(user).(*userv1.User)
+	want.Role = userv1.UserRole_USER_ROLE_USER
+	if echo.ServerId != 1 || string(echo.ClientData) != "hello" || !proto.Equal(echo.User, want) {
+		t.Fatalf("EchoData 返回异常: %v", echo)
+	}
+
+	client := giftv1.NewGiftServiceClient(conn)
+	gift, err := client.SendGift(ctx, &giftv1.SendGiftRequest{SenderId: 1, ReceiverId: 2, Price: 10, GiftType: giftv1.GiftType_GIFT_TYPE_SPECIAL, Quantity: 3})
+	if err != nil {
+		t.Fatalf("调用 SendGift 失败: %v", err)
+	}
+	if gift.GiftId != 100 || gift.SenderId != 1 || gift.GiftType != giftv1.GiftType_GIFT_TYPE_SPECIAL || gift.Quantity != 3 {
+		t.Fatalf("SendGift 返回异常: %v", gift)
+	}
+	top, err := client.GetTop10Senders(ctx, &giftv1.GetTop10SendersRequest{})
+	if err != nil || len(top.Result) != 3 {
+		t.Fatalf("GetTop10Senders 返回异常: %v, %v", top, err)
+	}
+	gifts, err := client.GetGiftsBySender(ctx, &giftv1.GetGiftsBySenderRequest{SenderId: 42})
+	if err != nil || len(gifts.Result) != 1 || gifts.Result[0].SenderId != 42 {
+		t.Fatalf("GetGiftsBySender 返回异常: %v, %v", gifts, err)
+	}
+}
+
+// TestGRPCServerErrors 测试 thrift 异常映射为对应的 gRPC 状态码
+func TestGRPCServerErrors(t *testing.T) {
+	client := giftv1.NewGiftServiceClient(startTestGRPCServer(t, &errorGiftService{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.GetTop10Senders(ctx, &giftv1.GetTop10SendersRequest{})
+	if status.Code(err) != codes.DeadlineExceeded || errors.Reason(err) != "TIMEOUT" {
+		t.Fatalf("期望 DeadlineExceeded TIMEOUT, 实际: %v", err)
+	}
+	_, err = client.GetSendersInLastWeek(ctx, &giftv1.GetSendersInLastWeekRequest{})
+	if status.Code(err) != codes.Unavailable || errors.Reason(err) != "LOADSHEDDING" {
+		t.Fatalf("期望 Unavailable LOADSHEDDING, 实际: %v", err)
+	}
+	// 返回 nil 时为空列表
+	gifts, err := client.GetGiftsBySender(ctx, &giftv1.GetGiftsBySenderRequest{SenderId: 1})
+	if err != nil || len(gifts.Result) != 0 {
+		t.Fatalf("GetGiftsBySender 返回异常: %v, %v", gifts, err)
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"encoding/json"
+	nethttp "net/http"
+
+	"aboveThriftRPC/internal/pkg/health"
+
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// registerHealth 注册 /healthz 与 /readyz
+//
+// /healthz 只要进程能响应就返回 200；/readyz 执行依赖检查，
+// 依赖不可用、服务未开始监听或正在停止时返回 503，响应体为各服务与依赖的状态。
+func registerHealth(srv *http.Server, h *health.Server) {
+	srv.HandleFunc("/healthz", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("ok"))
+	})
+	srv.HandleFunc("/readyz", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		report := h.Readiness(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status != health.ServingStatus_SERVING {
+			w.WriteHeader(nethttp.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+}
@@ -1,20 +1,26 @@
 package server
 
 import (
+	nethttp "net/http"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	"aboveThriftRPC/api/gen-go/user_service"
 	"aboveThriftRPC/internal/conf"
-	"aboveThriftRPC/internal/service"
+	"aboveThriftRPC/internal/pkg/thriftx"
 
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/go-kratos/kratos/v2/middleware/recovery"
 	"github.com/go-kratos/kratos/v2/transport/http"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // NewHTTPServer new an HTTP server.
-func NewHTTPServer(c *conf.Server, user *service.UserService, logger log.Logger) *http.Server {
+func NewHTTPServer(c *conf.Server, ts *ThriftServer, user user_service.UserService, gift gift_service.GiftService, logger log.Logger) *http.Server {
 	var opts = []http.ServerOption{
 		http.Middleware(
 			recovery.Recovery(),
 		),
+		http.ErrorEncoder(errorEncoder),
 	}
 	if c.Http.Network != "" {
 		opts = append(opts, http.Network(c.Http.Network))
@@ -26,7 +32,19 @@ func NewHTTPServer(c *conf.Server, user *service.UserService, logger log.Logger)
 		opts = append(opts, http.Timeout(c.Http.Timeout.AsDuration()))
 	}
 	srv := http.NewServer(opts...)
-	// 注意：这里可能需要根据实际 API 进行调整
-	// v1.RegisterUserHTTPServer(srv, user)
+	// 与 thrift 服务端共用多路处理器，中间件、超时与请求数上限保持一致
+	registerThriftHTTP(srv, c.Http.ThriftPath, ts.processor, ts.protocolFactory, ts.maxMessageSize)
+	// 存活与就绪检查，就绪状态与 thrift 健康检查服务一致
+	registerHealth(srv, ts.health)
+	// Prometheus 指标，包括 thrift 服务端与本进程中连接池的指标
+	srv.Handle("/metrics", promhttp.Handler())
+	// 由 IDL 生成的 JSON/REST 网关，路由见 openapi.yaml
+	user_service.RegisterUserServiceHTTPServer(srv, user)
+	gift_service.RegisterGiftServiceHTTPServer(srv, gift)
 	return srv
 }
+
+// errorEncoder 把 thrift 异常映射为对应的 HTTP 状态码，再按 kratos 默认的格式返回
+func errorEncoder(w nethttp.ResponseWriter, r *nethttp.Request, err error) {
+	http.DefaultErrorEncoder(w, r, thriftx.FromError(err))
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"strings"
+	"testing"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/pkg/thriftx"
+)
+
+// errorGiftService 测试错误映射用的礼物服务
+type errorGiftService struct {
+	thrifttest.GiftService
+}
+
+func (s *errorGiftService) SendGift(ctx context.Context, senderId int64, receiverId int64, price int32, giftType gift_service.GiftType, quantity int32) (*gift_service.Gift, error) {
+	return nil, nil
+}
+
+func (s *errorGiftService) GetTop10Senders(ctx context.Context) ([]int64, error) {
+	return nil, thriftx.NewTimeoutException("slow")
+}
+
+func (s *errorGiftService) GetSendersInLastWeek(ctx context.Context) ([]int64, error) {
+	return nil, thriftx.NewLoadSheddingException("busy")
+}
+
+func (s *errorGiftService) GetGiftsBySender(ctx context.Context, senderId int64) ([]*gift_service.Gift, error) {
+	return nil, nil
+}
+
+// doJSON 发送请求并返回状态码与响应体
+func doJSON(t *testing.T, method, url, body string) (int, string) {
+	t.Helper()
+	req, err := nethttp.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("创建请求失败: %v", err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := nethttp.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s 失败: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("读取响应失败: %v", err)
+	}
+	return resp.StatusCode, strings.TrimSpace(string(data))
+}
+
+// TestHTTPGateway 测试 JSON 请求体、路径参数绑定与 JSON 响应
+func TestHTTPGateway(t *testing.T) {
+	baseURL := startTestHTTPServer(t, &conf.Server{Thrift: &conf.Server_Thrift{}, Http: &conf.Server_HTTP{}})
+
+	code, body := doJSON(t, "POST", baseURL+"/v1/gifts",
+		`{"senderId":1,"receiverId":2,"price":10,"giftType":"GIFT_TYPE_SPECIAL","quantity":3}`)
+	if code != 200 {
+		t.Fatalf("SendGift 返回 %d: %s", code, body)
+	}
+	var gift gift_service.Gift
+	if err := json.Unmarshal([]byte(body), &gift); err != nil {
+		t.Fatalf("解析 SendGift 响应失败: %v, %s", err, body)
+	}
+	if gift.SenderId != 1 || gift.ReceiverId != 2 || gift.GiftType != gift_service.GiftType_GIFT_TYPE_SPECIAL || gift.Quantity != 3 {
+		t.Fatalf("SendGift 参数绑定错误: %s", body)
+	}
+	if !strings.Contains(body, `"giftType":"GIFT_TYPE_SPECIAL"`) {
+		t.Fatalf("枚举应编码为名字: %s", body)
+	}
+
+	if code, body := doJSON(t, "GET", baseURL+"/v1/senders/42/gifts", ""); code != 200 || !strings.Contains(body, `"senderId":42`) {
+		t.Fatalf("GetGiftsBySender 返回 %d: %s", code, body)
+	}
+	if code, body := doJSON(t, "GET", baseURL+"/v1/senders/top10", ""); code != 200 || body != "[1,2,3]" {
+		t.Fatalf("GetTop10Senders 返回 %d: %s", code, body)
+	}
+
+	// binary 使用 base64，未传的字段使用 IDL 中的默认值
+	code, body = doJSON(t, "POST", baseURL+"/v1/echo", `{"clientData":"aGVsbG8=","user":{"id":7}}`)
+	if code != 200 || !strings.Contains(body, `"clientData":"aGVsbG8="`) ||
+		!strings.Contains(body, `"id":7`) || !strings.Contains(body, `"role":"USER"`) {
+		t.Fatalf("echoData 返回 %d: %s", code, body)
+	}
+}
+
+// TestHTTPGatewayErrors 测试参数错误与 thrift 异常映射为对应的 HTTP 状态码
+func TestHTTPGatewayErrors(t *testing.T) {
+	baseURL := startTestHTTPServerWith(t, &conf.Server{Thrift: &conf.Server_Thrift{}, Http: &conf.Server_HTTP{}}, &errorGiftService{})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+		reason string
+	}{
+		{"路径参数错误", "GET", "/v1/senders/abc/gifts", "", 400, "CODEC"},
+		{"请求体错误", "POST", "/v1/gifts", `{"giftType":"NOPE"}`, 400, "CODEC"},
+		{"超时", "GET", "/v1/senders/top10", "", 504, "TIMEOUT"},
+		{"过载", "GET", "/v1/senders/last-week", "", 503, "LOADSHEDDING"},
+		{"缺少返回值", "POST", "/v1/gifts", `{}`, 500, "MISSING_RESULT"},
+	}
+	for _, tt := range tests {
+		code, body := doJSON(t, tt.method, baseURL+tt.path, tt.body)
+		var status struct {
+			Code   int    `json:"code"`
+			Reason string `json:"reason"`
+		}
+		if err := json.Unmarshal([]byte(body), &status); err != nil {
+			t.Fatalf("%s: 解析错误响应失败: %v, %s", tt.name, err, body)
+		}
+		if code != tt.code || status.Code != tt.code || status.Reason != tt.reason {
+			t.Errorf("%s: 期望 %d %s, 实际 %d %s", tt.name, tt.code, tt.reason, code, body)
+		}
+	}
+
+	// 返回 nil 列表时编码为空数组
+	if code, body := doJSON(t, "GET", baseURL+"/v1/senders/1/gifts", ""); code != 200 || body != "[]" {
+		t.Fatalf("GetGiftsBySender 返回 %d: %s", code, body)
+	}
+}
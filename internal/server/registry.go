@@ -0,0 +1,25 @@
+package server
+
+import (
+	"fmt"
+
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/registry/file"
+
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+// NewRegistrar 按配置创建服务注册中心，未配置时返回 nil，应用不做注册
+func NewRegistrar(c *conf.Registry) (registry.Registrar, error) {
+	switch c.GetType() {
+	case "":
+		return nil, nil
+	case "file":
+		if c.Path == "" {
+			return nil, fmt.Errorf("file 注册中心需要配置 path")
+		}
+		return file.New(c.Path), nil
+	default:
+		return nil, fmt.Errorf("不支持的注册中心类型: %s", c.Type)
+	}
+}
@@ -1,30 +1,92 @@
 package server
 
 import (
+	"aboveThriftRPC/api"
 	"aboveThriftRPC/api/gen-go/gift_service"
 	"aboveThriftRPC/api/gen-go/user_service"
 	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/accesslog"
+	"aboveThriftRPC/internal/pkg/health"
+	"aboveThriftRPC/internal/pkg/reflection"
+	"aboveThriftRPC/internal/pkg/thriftx"
 	"context"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/middleware/tracing"
+	"github.com/go-kratos/kratos/v2/transport"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	_ transport.Server     = (*ThriftServer)(nil)
+	_ transport.Endpointer = (*ThriftServer)(nil)
+)
+
 // ThriftServer 基于 thrift 的服务端封装
 type ThriftServer struct {
-	addr   string
-	server *thrift.TSimpleServer
+	network    string
+	addr       string
+	secure     bool
+	endpoint   string
+	server     *thriftServe
+	middleware []middleware.Middleware
+	health     *health.Server
+
+	// processor、protocolFactory 与 maxMessageSize 同时用于 thrift over http
+	processor       thrift.TProcessor
+	protocolFactory thrift.TProtocolFactory
+	maxMessageSize  int64
+}
+
+// NewThriftServerOptions 提供 thrift 服务端的默认选项
+func NewThriftServerOptions(h *health.Server) []ThriftServerOption {
+	return []ThriftServerOption{
+		// panic 由内置的 recoveryProcessor 处理，这里不需要 recovery 中间件
+		ThriftMiddleware(
+			// 从 THeader 请求头中提取 W3C trace context，为每个方法创建 server span
+			tracing.Server(),
+		),
+		ThriftHealth(h),
+	}
 }
 
 // NewThriftServer 创建一个新的 thrift 服务端
-func NewThriftServer(c *conf.Server, user user_service.UserService, gift gift_service.GiftService) (*ThriftServer, error) {
+func NewThriftServer(c *conf.Server, user user_service.UserService, gift gift_service.GiftService, opts ...ThriftServerOption) (*ThriftServer, error) {
+	srv := &ThriftServer{
+		network: c.Thrift.Network,
+		addr:    c.Thrift.Addr,
+		secure:  c.Thrift.GetTls().GetCertFile() != "",
+	}
+	for _, o := range opts {
+		o(srv)
+	}
+	if srv.health == nil {
+		srv.health = health.NewServer(nil)
+	}
+
+	policy := c.Thrift.OverloadPolicy
+	switch policy {
+	case "":
+		policy = OverloadReject
+	case OverloadReject, OverloadQueue:
+	default:
+		return nil, fmt.Errorf("不支持的过载策略: %s", policy)
+	}
+
 	// 创建监听地址
-	transport, err := thrift.NewTServerSocket(c.Thrift.Addr)
+	transport, err := newServerTransport(c.Thrift)
 	if err != nil {
 		return nil, fmt.Errorf("创建监听套接字失败: %w", err)
 	}
+	endpoint, err := thriftx.NewEndpoint(srv.network, srv.addr, srv.secure)
+	if err != nil {
+		return nil, err
+	}
+	srv.endpoint = endpoint.String()
 
 	// 创建多路处理器
 	processor := thrift.NewTMultiplexedProcessor()
@@ -33,48 +95,130 @@ func NewThriftServer(c *conf.Server, user user_service.UserService, gift gift_se
 	processor.RegisterProcessor("UserService", user_service.NewUserServiceProcessor(user))
 	// 注册礼物服务处理器
 	processor.RegisterProcessor("GiftService", gift_service.NewGiftServiceProcessor(gift))
-
-	protocolFactory := thrift.NewTBinaryProtocolFactoryConf(
-		&thrift.TConfiguration{
-			MaxMessageSize:     16 * 1024 * 1024, // 16 MB
-			MaxFrameSize:       16 * 1024 * 1024, // 16 MB
-			TBinaryStrictRead:  thrift.BoolPtr(false),
-			TBinaryStrictWrite: thrift.BoolPtr(false),
-			ConnectTimeout:     5 * time.Second,
-			SocketTimeout:      10 * time.Second,
-			TLSConfig:          nil, // 禁用 TLS
-		},
+	// 注册健康检查服务
+	processor.RegisterProcessor(health.ServiceName, health.NewHealthProcessor(srv.health))
+	// 注册反射服务，可以查询已注册的服务、方法签名与 .thrift 源码
+	reflectionServer, err := reflection.NewServer(processor, api.IDL, health.IDL)
+	if err != nil {
+		return nil, err
+	}
+	processor.RegisterProcessor(reflection.ServiceName, reflection.NewReflectionProcessor(reflectionServer))
+	// 参数在 kratos 中间件之前解码，每个方法都需要登记参数与结果结构体
+	for name := range processor.ProcessorMap() {
+		if _, ok := thriftMethods[name]; !ok {
+			return nil, fmt.Errorf("thrift 方法 %s 没有登记参数与结果结构体", name)
+		}
+	}
+	// 开始监听前各服务为 NOT_SERVING
+	for _, service := range reflection.Services(processor) {
+		srv.health.SetServingStatus(service, health.ServingStatus_NOT_SERVING)
+	}
+	// 每个处理函数都注入 transport 并经过 kratos 中间件链，外层依次是访问日志、指标、请求大小、超时控制、panic 恢复与请求数上限
+	methodTimeouts := make(map[string]time.Duration, len(c.Thrift.MethodTimeouts))
+	for method, d := range c.Thrift.MethodTimeouts {
+		methodTimeouts[method] = d.AsDuration()
+	}
+	maxRequestSizes := make(map[string]int64, len(c.Thrift.MethodMaxRequestSizes))
+	for method, n := range c.Thrift.MethodMaxRequestSizes {
+		maxRequestSizes[method] = int64(n)
+	}
+	var processorMiddlewares []thrift.ProcessorMiddleware
+	if al := c.Thrift.GetAccessLog(); al.GetEnabled() {
+		l := &accessLog{sampleRate: al.SampleRate, slow: al.SlowThreshold.AsDuration()}
+		if al.LogPayload {
+			if l.decoder, err = accesslog.NewDecoder(al.RedactFields, api.IDL, health.IDL); err != nil {
+				return nil, err
+			}
+		}
+		processorMiddlewares = append(processorMiddlewares, l.processor())
+	}
+	processorMiddlewares = append(processorMiddlewares,
+		metricsProcessor(),
+		sizeLimitProcessor(maxRequestSizes),
+		timeoutProcessor(c.Thrift.Timeout.AsDuration(), methodTimeouts),
+		recoveryProcessor(),
+	)
+	if n := c.Thrift.MaxInFlight; n > 0 {
+		processorMiddlewares = append(processorMiddlewares,
+			limitProcessor(make(chan struct{}, n), policy, c.Thrift.QueueTimeout.AsDuration()))
+	}
+	processorMiddlewares = append(processorMiddlewares,
+		transportProcessor(srv.endpoint),
+		middlewareProcessor(middleware.Chain(srv.middleware...), thriftMethods),
 	)
+	thrift.WrapProcessor(processor, processorMiddlewares...)
 
-	transportFactory := thrift.NewTTransportFactory()
+	// 根据配置选择传输层与协议，默认 buffered + binary
+	srv.maxMessageSize = int64(c.Thrift.MaxMessageSize)
+	if srv.maxMessageSize <= 0 {
+		srv.maxMessageSize = defaultMaxMessageSize
+	}
+	maxFrameSize := c.Thrift.MaxFrameSize
+	if maxFrameSize <= 0 {
+		maxFrameSize = defaultMaxMessageSize
+	}
+	thriftConf := &thrift.TConfiguration{
+		MaxMessageSize:     int32(srv.maxMessageSize),
+		MaxFrameSize:       maxFrameSize,
+		TBinaryStrictRead:  thrift.BoolPtr(false),
+		TBinaryStrictWrite: thrift.BoolPtr(false),
+		ConnectTimeout:     5 * time.Second,
+		SocketTimeout:      10 * time.Second,
+	}
+	transportFactory, protocolFactory, err := thriftx.NewFactories(c.Thrift.Protocol, c.Thrift.Transport, thriftConf)
+	if err != nil {
+		return nil, err
+	}
+	if c.Thrift.Transport == thriftx.TransportFramed {
+		// 在读取帧内容之前按帧长度检查方法的请求上限
+		transportFactory = framedTransportFactory{cfg: thriftConf}
+	}
+	srv.processor = processor
+	srv.protocolFactory = protocolFactory
 
-	// 创建简单服务器
-	server := thrift.NewTSimpleServer4(
+	// 创建有界的服务循环，连接数与排队长度由配置决定
+	srv.server = newThriftServe(
 		processor,
 		transport,
 		transportFactory,
 		protocolFactory,
+		int(c.Thrift.MaxConnections),
+		int(c.Thrift.AcceptBacklog),
+		policy,
 	)
 
-	return &ThriftServer{
-		addr:   c.Thrift.Addr,
-		server: server,
-	}, nil
+	return srv, nil
 }
 
-// Start 启动 thrift 服务端监听
+// Endpoint 返回注册到服务发现的地址，如 thrift://192.168.1.10:9000 或 thrift+unix:///run/above.sock，
+// 尚未监听时会先监听
+func (s *ThriftServer) Endpoint() (*url.URL, error) {
+	if err := s.server.Listen(); err != nil {
+		return nil, fmt.Errorf("thrift server listen on %s: %w", s.addr, err)
+	}
+	addr := s.addr
+	if a := s.server.Addr(); a != nil {
+		addr = a.String()
+	}
+	return thriftx.NewEndpoint(s.network, addr, s.secure)
+}
+
+// Start 监听地址并处理连接，直到 Stop 被调用，监听失败时直接返回错误
 func (s *ThriftServer) Start(ctx context.Context) error {
-	logrus.Infof("thrift server starting listening on: %s", s.addr)
-	go func() {
-		if err := s.server.Serve(); err != nil {
-			logrus.Errorf("thrift server serve error: %v", err)
-		}
-	}()
-	return nil
+	if err := s.server.Listen(); err != nil {
+		return fmt.Errorf("thrift server listen on %s: %w", s.addr, err)
+	}
+	logrus.Infof("thrift server listening on: %s", s.addr)
+	for _, service := range reflection.Services(s.processor) {
+		s.health.SetServingStatus(service, health.ServingStatus_SERVING)
+	}
+	return s.server.Serve()
 }
 
-// Stop 优雅停止 thrift 服务端
+// Stop 优雅停止 thrift 服务端，不再接受新连接，处理中的请求在 ctx 到期前可以完成，
+// 停止期间健康检查报告 NOT_SERVING
 func (s *ThriftServer) Stop(ctx context.Context) error {
 	logrus.Info("thrift server stopping")
-	return s.server.Stop()
+	s.health.Shutdown()
+	return s.server.Stop(ctx)
 }
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"time"
+
+	"aboveThriftRPC/internal/pkg/accesslog"
+	"aboveThriftRPC/internal/pkg/metrics"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/sirupsen/logrus"
+)
+
+type peerKey struct{}
+
+// withPeer 把客户端地址放入 context，供访问日志使用
+func withPeer(ctx context.Context, peer string) context.Context {
+	return context.WithValue(ctx, peerKey{}, peer)
+}
+
+func peerFromContext(ctx context.Context) string {
+	peer, _ := ctx.Value(peerKey{}).(string)
+	return peer
+}
+
+// peerAddr 连接的客户端地址，unix socket 的客户端通常没有地址
+func peerAddr(client thrift.TTransport) string {
+	if c, ok := client.(*countedConn); ok {
+		client = c.TTransport
+	}
+	if s, ok := client.(interface{ Conn() net.Conn }); ok && s.Conn() != nil {
+		if addr := s.Conn().RemoteAddr(); addr != nil {
+			return addr.String()
+		}
+	}
+	return ""
+}
+
+// accessLog 每次调用输出一条访问日志
+//
+// 成功的请求按 sampleRate 采样，失败与耗时超过 slow 的请求总是记录。
+// decoder 不为空时同时记录脱敏后的请求参数与响应结果。
+type accessLog struct {
+	sampleRate float64
+	slow       time.Duration
+	decoder    *accesslog.Decoder
+}
+
+// sampled 判断是否记录本次调用
+func (l *accessLog) sampled(code string, d time.Duration) bool {
+	if code != metrics.CodeOK || (l.slow > 0 && d >= l.slow) {
+		return true
+	}
+	return l.sampleRate <= 0 || l.sampleRate >= 1 || rand.Float64() < l.sampleRate
+}
+
+// processor 位于最外层，耗时包含排队与超时控制，请求与响应的字节数为连接上实际读写的字节数
+func (l *accessLog) processor() thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		service, method, ok := strings.Cut(name, thrift.MULTIPLEXED_SEPARATOR)
+		if !ok {
+			service, method = "", name
+		}
+		operation := service + "." + method
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				start := time.Now()
+				var args, reply *thrift.TMemoryBuffer
+				if l.decoder != nil {
+					// 读取的参数与写回的响应同时以 binary 协议复制一份，按 IDL 解码后写入日志
+					args, reply = thrift.NewTMemoryBuffer(), thrift.NewTMemoryBuffer()
+					in = &thrift.TDuplicateToProtocol{Delegate: in, DuplicateTo: thrift.NewTBinaryProtocolConf(args, nil)}
+					out = &thrift.TDuplicateToProtocol{Delegate: out, DuplicateTo: thrift.NewTBinaryProtocolConf(reply, nil)}
+				}
+				ok, exc := next.Process(ctx, seqID, in, out)
+				latency := time.Since(start)
+				code := metrics.Code(exc)
+				if !l.sampled(code, latency) {
+					return ok, exc
+				}
+
+				fields := logrus.Fields{
+					"service": service,
+					"method":  method,
+					"peer":    peerFromContext(ctx),
+					"seq_id":  seqID,
+					"latency": latency.Seconds(),
+					"code":    code,
+				}
+				if meter, ok := ctx.Value(sizeMeterKey{}).(*sizeMeter); ok {
+					fields["request_size"], fields["response_size"] = meter.sizes()
+				}
+				if exc != nil {
+					fields["error"] = exc.Error()
+				}
+				if l.decoder != nil {
+					if req, err := l.decoder.Args(ctx, operation, args.Bytes()); err == nil {
+						fields["request"] = req
+					}
+					if resp, err := l.decoder.Result(ctx, operation, reply.Bytes()); err == nil && resp != nil {
+						fields["response"] = resp
+					}
+				}
+				entry := logrus.WithFields(fields)
+				if exc != nil {
+					entry.Warn("thrift access")
+				} else {
+					entry.Info("thrift access")
+				}
+				return ok, exc
+			},
+		}
+	}
+}
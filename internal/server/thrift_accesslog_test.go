@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/accesslog"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// accessEntries 返回 hook 收到的访问日志
+func accessEntries(hook *test.Hook) []logrus.Entry {
+	var entries []logrus.Entry
+	for _, e := range hook.AllEntries() {
+		if e.Message == "thrift access" {
+			entries = append(entries, *e)
+		}
+	}
+	return entries
+}
+
+// TestThriftAccessLog 测试访问日志记录调用信息，请求与响应中的敏感字段被脱敏
+func TestThriftAccessLog(t *testing.T) {
+	hook := test.NewGlobal()
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+	addr := startTestServer(t, &conf.Server_Thrift{AccessLog: &conf.Server_Thrift_AccessLog{Enabled: true, LogPayload: true}})
+	client, _ := dialUserClient(t, addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	phone := "13800000000"
+	user := &user_service.User{ID: 1, Email: "alice@example.com", Details: &user_service.UserDetails{Phone: &phone}, Avatar: []byte("png")}
+	if _, err := client.EchoData(ctx, []byte("secret"), user); err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+
+	entries := accessEntries(hook)
+	if len(entries) != 1 {
+		t.Fatalf("期望 1 条访问日志, 实际 %d", len(entries))
+	}
+	e := entries[0]
+	if e.Data["service"] != "UserService" || e.Data["method"] != "echoData" || e.Data["code"] != "OK" {
+		t.Fatalf("访问日志字段不一致: %v", e.Data)
+	}
+	if peer, _ := e.Data["peer"].(string); !strings.HasPrefix(peer, "127.0.0.1:") {
+		t.Errorf("期望记录客户端地址, 实际 %q", peer)
+	}
+	if n, _ := e.Data["request_size"].(int64); n <= 0 {
+		t.Errorf("期望记录请求大小, 实际 %v", e.Data["request_size"])
+	}
+	if n, _ := e.Data["response_size"].(int64); n <= 0 {
+		t.Errorf("期望记录响应大小, 实际 %v", e.Data["response_size"])
+	}
+	if _, ok := e.Data["latency"].(float64); !ok {
+		t.Errorf("期望记录耗时, 实际 %v", e.Data["latency"])
+	}
+	line, err := e.String()
+	if err != nil {
+		t.Fatalf("格式化日志失败: %v", err)
+	}
+	for _, secret := range []string{"alice@example.com", phone, "secret", "png"} {
+		if strings.Contains(line, secret) {
+			t.Errorf("访问日志中包含敏感数据 %q: %s", secret, line)
+		}
+	}
+	req, _ := e.Data["request"].(map[string]any)
+	if req["clientData"] != "[6 bytes]" || req["user"].(map[string]any)["email"] != accesslog.Redacted {
+		t.Errorf("请求参数脱敏不一致: %v", req)
+	}
+	if _, ok := e.Data["response"].(map[string]any)["success"]; !ok {
+		t.Errorf("期望记录响应结果, 实际 %v", e.Data["response"])
+	}
+}
+
+// TestThriftAccessLogSampling 测试成功的请求按比例采样，失败的请求总是记录
+func TestThriftAccessLogSampling(t *testing.T) {
+	hook := test.NewGlobal()
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+	_, addr := startTestServerWith(t, &conf.Server_Thrift{AccessLog: &conf.Server_Thrift_AccessLog{Enabled: true, SampleRate: 1e-9}},
+		&thrifttest.UserService{}, ThriftMiddleware(recovery.Recovery()))
+	client, _ := dialUserClient(t, addr)
+
+	for i := 0; i < 5; i++ {
+		if err := callEcho(client, "hello"); err != nil {
+			t.Fatalf("调用失败: %v", err)
+		}
+	}
+	if err := callEcho(client, "panic"); err == nil {
+		t.Fatal("期望 panic 的请求失败")
+	}
+	entries := accessEntries(hook)
+	if len(entries) != 1 {
+		t.Fatalf("期望只记录失败的请求, 实际 %d 条", len(entries))
+	}
+	if e := entries[0]; e.Level != logrus.WarnLevel || e.Data["code"] != "INTERNAL_ERROR" || e.Data["request"] != nil {
+		t.Fatalf("失败请求的访问日志不一致: %v %v", e.Level, e.Data)
+	}
+}
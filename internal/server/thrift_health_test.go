@@ -0,0 +1,85 @@
+package server
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/health"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/log"
+)
+
+// TestThriftHealth 测试健康检查服务与 /healthz、/readyz，停止期间报告 NOT_SERVING
+func TestThriftHealth(t *testing.T) {
+	user := newBlockingUserService()
+	ts, addr := startTestServerWith(t, &conf.Server_Thrift{}, user)
+	hs := httptest.NewServer(NewHTTPServer(&conf.Server{Http: &conf.Server_HTTP{}}, ts, user, &thrifttest.GiftService{}, log.DefaultLogger))
+	defer hs.Close()
+
+	socket := thrift.NewTSocketConf(addr, &thrift.TConfiguration{ConnectTimeout: time.Second, SocketTimeout: 5 * time.Second})
+	transport := thrift.NewTBufferedTransport(socket, 2048)
+	if err := transport.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	defer transport.Close()
+	client := health.NewClientProtocol(thrift.NewTBinaryProtocolConf(transport, nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, service := range []string{"", "UserService", "GiftService"} {
+		if resp, err := client.Check(ctx, service); err != nil || resp.Status != health.ServingStatus_SERVING {
+			t.Fatalf("Check(%q) 返回 %v, %v", service, resp, err)
+		}
+	}
+	statuses, err := client.List(ctx)
+	if err != nil || statuses[health.ServiceName] != health.ServingStatus_SERVING || statuses["Reflection"] != health.ServingStatus_SERVING {
+		t.Fatalf("List 返回 %v, %v", statuses, err)
+	}
+
+	if code, body := doJSON(t, "GET", hs.URL+"/healthz", ""); code != 200 || body != "ok" {
+		t.Fatalf("/healthz 返回 %d: %s", code, body)
+	}
+	if code, body := doJSON(t, "GET", hs.URL+"/readyz", ""); code != 200 || !strings.Contains(body, `"GiftService":"SERVING"`) {
+		t.Fatalf("/readyz 返回 %d: %s", code, body)
+	}
+
+	// 有请求在处理时停止，排空期间就绪检查失败，存活检查不受影响
+	slow, _ := dialUserClient(t, addr)
+	done := make(chan error, 1)
+	go func() {
+		done <- callEcho(slow, "block")
+	}()
+	<-user.started
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- ts.Stop(ctx)
+	}()
+	var code int
+	var body string
+	for i := 0; i < 50; i++ {
+		if code, body = doJSON(t, "GET", hs.URL+"/readyz", ""); code == 503 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if code != 503 || !strings.Contains(body, `"status":"NOT_SERVING"`) || !strings.Contains(body, `"UserService":"NOT_SERVING"`) {
+		t.Fatalf("排空期间 /readyz 返回 %d: %s", code, body)
+	}
+	if code, _ := doJSON(t, "GET", hs.URL+"/healthz", ""); code != 200 {
+		t.Fatalf("排空期间 /healthz 返回 %d", code)
+	}
+
+	close(user.release)
+	if err := <-done; err != nil {
+		t.Fatalf("处理中的请求未能完成: %v", err)
+	}
+	if err := <-stopped; err != nil {
+		t.Fatalf("Stop 返回错误: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	nethttp "net/http"
+	"strings"
+
+	"aboveThriftRPC/internal/pkg/reflection"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/transport/http"
+)
+
+// defaultThriftPath thrift over http 的默认路径
+const defaultThriftPath = "/thrift"
+
+// registerThriftHTTP 在 HTTP 服务上挂载 thrift 处理器，语义与 THttpClient 一致：
+// 每个 POST 请求体是一条 thrift 消息，响应体是对应的回复，协议与 thrift 服务端的配置相同。
+//
+// path 接收 TMultiplexedProtocol 编码的请求；path/{Service} 接收不带服务名前缀的请求，
+// 供生成的 *-remote 客户端等不使用多路复用的调用方使用。请求体不能超过 maxMessageSize。
+func registerThriftHTTP(srv *http.Server, path string, processor thrift.TProcessor, protocolFactory thrift.TProtocolFactory, maxMessageSize int64) {
+	if path == "" {
+		path = defaultThriftPath
+	}
+	srv.Handle(path, thriftHTTPHandler(processor, protocolFactory, protocolFactory, maxMessageSize))
+	base := strings.TrimSuffix(path, "/")
+	for _, service := range reflection.Services(processor) {
+		in := &serviceProtocolFactory{TProtocolFactory: protocolFactory, service: service}
+		srv.Handle(base+"/"+service, thriftHTTPHandler(processor, in, protocolFactory, maxMessageSize))
+	}
+}
+
+// thriftHTTPHandler 只接受 POST 请求，其余方法返回 405，请求体与响应体经过 sizeMeter 计数，用于按方法的大小上限与访问日志
+func thriftHTTPHandler(processor thrift.TProcessor, in, out thrift.TProtocolFactory, maxMessageSize int64) nethttp.Handler {
+	h := thrift.NewThriftHandlerFunc(processor, in, out)
+	return nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Method != nethttp.MethodPost {
+			w.Header().Set("Allow", nethttp.MethodPost)
+			nethttp.Error(w, "thrift over http 只支持 POST", nethttp.StatusMethodNotAllowed)
+			return
+		}
+		meter := &sizeMeter{}
+		r.Body = &meteredReader{ReadCloser: nethttp.MaxBytesReader(w, r.Body, maxMessageSize), meter: meter}
+		h(&meteredWriter{ResponseWriter: w, meter: meter}, r.WithContext(withPeer(withSizeMeter(r.Context(), meter), r.RemoteAddr)))
+	})
+}
+
+// serviceProtocolFactory 为不带服务名的请求补上服务名前缀，交给多路处理器分发
+type serviceProtocolFactory struct {
+	thrift.TProtocolFactory
+	service string
+}
+
+func (f *serviceProtocolFactory) GetProtocol(trans thrift.TTransport) thrift.TProtocol {
+	return &serviceProtocol{TProtocol: f.TProtocolFactory.GetProtocol(trans), service: f.service}
+}
+
+type serviceProtocol struct {
+	thrift.TProtocol
+	service string
+}
+
+func (p *serviceProtocol) ReadMessageBegin(ctx context.Context) (string, thrift.TMessageType, int32, error) {
+	name, typeID, seqID, err := p.TProtocol.ReadMessageBegin(ctx)
+	if err == nil && !strings.Contains(name, thrift.MULTIPLEXED_SEPARATOR) {
+		name = p.service + thrift.MULTIPLEXED_SEPARATOR + name
+	}
+	return name, typeID, seqID, err
+}
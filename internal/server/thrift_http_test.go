@@ -0,0 +1,120 @@
+package server
+
+import (
+	nethttp "net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/log"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// startTestHTTPServer 启动挂载了 thrift 处理器的 HTTP 服务端，返回 http://addr
+func startTestHTTPServer(t *testing.T, c *conf.Server) string {
+	t.Helper()
+	return startTestHTTPServerWith(t, c, &thrifttest.GiftService{})
+}
+
+// startTestHTTPServerWith 使用指定的礼物服务启动 HTTP 服务端
+func startTestHTTPServerWith(t *testing.T, c *conf.Server, gift gift_service.GiftService) string {
+	t.Helper()
+	if c.Thrift.Addr == "" {
+		c.Thrift.Addr = thrifttest.FreeAddr(t)
+	}
+	if c.Http.Addr == "" {
+		c.Http.Addr = thrifttest.FreeAddr(t)
+	}
+	ts, err := NewThriftServer(c, &thrifttest.UserService{}, gift)
+	if err != nil {
+		t.Fatalf("创建 thrift 服务端失败: %v", err)
+	}
+	srv := NewHTTPServer(c, ts, &thrifttest.UserService{}, gift, log.DefaultLogger)
+	thrifttest.Start(t, srv, "tcp", c.Http.Addr)
+	return "http://" + c.Http.Addr
+}
+
+// buildRemote 编译生成的 *-remote 客户端，返回可执行文件路径
+func buildRemote(t *testing.T, pkg string) string {
+	t.Helper()
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("找不到 go 命令，跳过 remote 客户端测试")
+	}
+	bin := filepath.Join(t.TempDir(), filepath.Base(pkg))
+	if out, err := exec.Command(goBin, "build", "-o", bin, pkg).CombinedOutput(); err != nil {
+		t.Fatalf("编译 %s 失败: %v\n%s", pkg, err, out)
+	}
+	return bin
+}
+
+// runRemote 以 -http 模式运行 remote 客户端，返回标准输出
+func runRemote(t *testing.T, bin, url string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(bin, append([]string{"-http", "-u", url}, args...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("运行 %s 失败: %v\n%s", filepath.Base(bin), err, stderr.String())
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// TestThriftHTTPRemote 测试生成的 remote 客户端以 -http 模式调用各个服务
+func TestThriftHTTPRemote(t *testing.T) {
+	userRemote := buildRemote(t, "aboveThriftRPC/api/gen-go/user_service/user_service-remote")
+	giftRemote := buildRemote(t, "aboveThriftRPC/api/gen-go/gift_service/gift_service-remote")
+	baseURL := startTestHTTPServer(t, &conf.Server{
+		Thrift: &conf.Server_Thrift{},
+		Http:   &conf.Server_HTTP{ThriftPath: "/rpc", Timeout: durationpb.New(time.Second)},
+	})
+
+	out := runRemote(t, userRemote, baseURL+"/rpc/UserService", "echoData", "hello", `{"1":{"i64":7}}`)
+	if !strings.HasSuffix(out, "<nil>") || !strings.Contains(out, "ID:7") {
+		t.Fatalf("echoData 返回异常: %s", out)
+	}
+	out = runRemote(t, giftRemote, baseURL+"/rpc/GiftService", "GetTop10Senders")
+	if out != "[1 2 3] <nil>" {
+		t.Fatalf("GetTop10Senders 返回异常: %s", out)
+	}
+	out = runRemote(t, giftRemote, baseURL+"/rpc/GiftService", "GetGiftsBySender", "42")
+	if !strings.HasSuffix(out, "<nil>") || !strings.Contains(out, "SenderId:42") {
+		t.Fatalf("GetGiftsBySender 返回异常: %s", out)
+	}
+}
+
+// TestThriftHTTPMultiplexed 测试默认路径上使用 TMultiplexedProtocol 调用，以及非 POST 请求被拒绝
+func TestThriftHTTPMultiplexed(t *testing.T) {
+	baseURL := startTestHTTPServer(t, &conf.Server{
+		Thrift: &conf.Server_Thrift{Protocol: "compact"},
+		Http:   &conf.Server_HTTP{},
+	})
+
+	trans, err := thrift.NewTHttpClient(baseURL + defaultThriftPath)
+	if err != nil {
+		t.Fatalf("创建 HTTP 客户端失败: %v", err)
+	}
+	protocol := thrift.NewTMultiplexedProtocol(thrift.NewTCompactProtocolConf(trans, nil), "UserService")
+	client := user_service.NewUserServiceClient(thrift.NewTStandardClient(protocol, protocol))
+	for i := 0; i < 2; i++ {
+		echo(t, client)
+	}
+
+	resp, err := nethttp.Get(baseURL + defaultThriftPath)
+	if err != nil {
+		t.Fatalf("GET 请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != nethttp.StatusMethodNotAllowed {
+		t.Fatalf("期望 GET 返回 405, 实际: %d", resp.StatusCode)
+	}
+}
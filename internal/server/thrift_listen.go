@@ -0,0 +1,149 @@
+package server
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+var _ thrift.TServerTransport = (*listenTransport)(nil)
+
+// listenTransport 按网络类型监听的 thrift.TServerTransport
+//
+// thrift 自带的 TServerSocket、TSSLServerSocket 只支持 tcp，这里支持 tcp、tcp4、tcp6、unix，
+// 配置了 TLS 时在监听之上包一层 TLS。unix socket 文件在监听前清理残留、监听后设置权限，
+// 关闭监听时由 net.UnixListener 删除。
+type listenTransport struct {
+	addr       thriftx.Addr
+	tlsConfig  *tls.Config
+	socketMode os.FileMode
+
+	mu       sync.Mutex
+	listener net.Listener
+}
+
+// newServerTransport 按配置创建监听，配置了证书时使用 TLS
+func newServerTransport(c *conf.Server_Thrift) (*listenTransport, error) {
+	addr, err := thriftx.NewAddr(c.Network, c.Addr)
+	if err != nil {
+		return nil, err
+	}
+	t := &listenTransport{addr: addr}
+	if mode := c.UnixSocketMode; mode != "" {
+		if addr.Net != thriftx.NetworkUnix {
+			return nil, fmt.Errorf("unix_socket_mode 只能用于 unix 网络")
+		}
+		m, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("解析 unix_socket_mode %q 失败: %w", mode, err)
+		}
+		t.socketMode = os.FileMode(m).Perm()
+	}
+	if tlsConf := c.GetTls(); tlsConf.GetCertFile() != "" {
+		t.tlsConfig, err = thriftx.NewServerTLSConfig(tlsConf.CertFile, tlsConf.KeyFile, tlsConf.CaFile, tlsConf.ClientAuth)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Listen 开始监听，重复调用不会重复监听
+func (t *listenTransport) Listen() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.listener != nil {
+		return nil
+	}
+	if t.addr.Net == thriftx.NetworkUnix {
+		if err := removeStaleSocket(t.addr.Address); err != nil {
+			return err
+		}
+	}
+	l, err := net.Listen(t.addr.Net, t.addr.Address)
+	if err != nil {
+		return err
+	}
+	if t.addr.Net == thriftx.NetworkUnix && t.socketMode != 0 {
+		if err := os.Chmod(t.addr.Address, t.socketMode); err != nil {
+			l.Close()
+			return fmt.Errorf("设置 unix socket 权限失败: %w", err)
+		}
+	}
+	if t.tlsConfig != nil {
+		l = tls.NewListener(l, t.tlsConfig)
+	}
+	t.listener = l
+	return nil
+}
+
+// Accept 等待新连接
+func (t *listenTransport) Accept() (thrift.TTransport, error) {
+	t.mu.Lock()
+	l := t.listener
+	t.mu.Unlock()
+	if l == nil {
+		return nil, thrift.NewTTransportException(thrift.NOT_OPEN, "No underlying server socket")
+	}
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, thrift.NewTTransportExceptionFromError(err)
+	}
+	return thrift.NewTSocketFromConnConf(conn, nil), nil
+}
+
+// Close 关闭监听，unix socket 文件随之删除
+func (t *listenTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.listener == nil {
+		return nil
+	}
+	err := t.listener.Close()
+	t.listener = nil
+	return err
+}
+
+// Interrupt 关闭监听让阻塞的 Accept 返回
+func (t *listenTransport) Interrupt() error {
+	return t.Close()
+}
+
+// Addr 返回实际监听的地址，未监听时返回 nil
+func (t *listenTransport) Addr() net.Addr {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.listener == nil {
+		return nil
+	}
+	return t.listener.Addr()
+}
+
+// removeStaleSocket 删除上次异常退出残留的 socket 文件，仍有进程在监听时返回错误
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("%s 已存在且不是 unix socket", path)
+	}
+	if conn, err := net.DialTimeout(thriftx.NetworkUnix, path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s 上已有服务在监听", path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// newUnixUserClient 通过 unix socket 创建 UserService 客户端
+func newUnixUserClient(t *testing.T, path string) *user_service.UserServiceClient {
+	t.Helper()
+	socket := thrift.NewTSocketFromAddrConf(thriftx.Addr{Net: thriftx.NetworkUnix, Address: path}, &thrift.TConfiguration{ConnectTimeout: time.Second, SocketTimeout: 5 * time.Second})
+	transport := thrift.NewTBufferedTransport(socket, 2048)
+	if err := transport.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	t.Cleanup(func() {
+		transport.Close()
+	})
+	protocol := thrift.NewTMultiplexedProtocol(thrift.NewTBinaryProtocolConf(transport, nil), "UserService")
+	return user_service.NewUserServiceClient(thrift.NewTStandardClient(protocol, protocol))
+}
+
+// staleSocket 创建一个没有进程监听的 socket 文件，模拟异常退出后的残留
+func staleSocket(t *testing.T, path string) {
+	t.Helper()
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("创建 socket 文件失败: %v", err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+}
+
+// TestThriftServerUnixSocket 测试监听 unix socket：清理残留文件、设置权限、停止后删除文件
+func TestThriftServerUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thrift.sock")
+	staleSocket(t, path)
+
+	srv, _ := startTestServerWith(t, &conf.Server_Thrift{Network: "unix", Addr: path, UnixSocketMode: "0600"}, &thrifttest.UserService{})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("socket 文件不存在: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("socket 文件权限不符合预期: %v", info.Mode().Perm())
+	}
+	echo(t, newUnixUserClient(t, path))
+
+	u, err := srv.Endpoint()
+	if err != nil || u.String() != "thrift+unix://"+path {
+		t.Fatalf("endpoint 不符合预期: %v, %v", u, err)
+	}
+
+	if err := srv.Stop(context.Background()); err != nil {
+		t.Fatalf("停止服务端失败: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("期望停止后删除 socket 文件, 实际: %v", err)
+	}
+}
+
+// TestThriftServerUnixSocketInUse 测试 socket 文件上已有服务在监听时启动失败
+func TestThriftServerUnixSocketInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thrift.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("监听 socket 失败: %v", err)
+	}
+	defer l.Close()
+
+	srv, err := NewThriftServer(&conf.Server{Thrift: &conf.Server_Thrift{Network: "unix", Addr: path}}, &thrifttest.UserService{}, &thrifttest.GiftService{})
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	if err := srv.Start(context.Background()); err == nil {
+		srv.Stop(context.Background())
+		t.Fatal("期望 socket 已被占用时启动失败")
+	}
+}
+
+// TestThriftServerNetwork 测试 tcp4 网络与不支持的网络配置
+func TestThriftServerNetwork(t *testing.T) {
+	echo(t, newTestUserClient(t, startTestServer(t, &conf.Server_Thrift{Network: "tcp4"}), binaryProtocol))
+
+	for _, c := range []*conf.Server_Thrift{
+		{Network: "udp", Addr: thrifttest.FreeAddr(t)},
+		{Network: "tcp", Addr: thrifttest.FreeAddr(t), UnixSocketMode: "0600"},
+		{Network: "unix", Addr: filepath.Join(t.TempDir(), "thrift.sock"), UnixSocketMode: "rw"},
+	} {
+		if _, err := NewThriftServer(&conf.Server{Thrift: c}, &thrifttest.UserService{}, &thrifttest.GiftService{}); err == nil {
+			t.Fatalf("期望配置 %v 返回错误", c)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"aboveThriftRPC/api/gen-go/gift_service"
+	healthgen "aboveThriftRPC/api/gen-go/health"
+	reflectiongen "aboveThriftRPC/api/gen-go/reflection"
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/pkg/health"
+	"aboveThriftRPC/internal/pkg/reflection"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// thriftMethod 方法的参数与结果结构体，middlewareProcessor 在 kratos 中间件之前按此解码参数
+type thriftMethod struct {
+	args   func() thrift.TStruct
+	result func() thrift.TStruct
+}
+
+// generatedMethods thrift-gen-methods 为每个服务生成的 {Service}Methods，键为方法名
+type generatedMethods = map[string]struct {
+	Args   func() thrift.TStruct
+	Result func() thrift.TStruct
+}
+
+// thriftMethods 多路处理器中注册的全部方法，键为 Service:method，由 thrift-gen-methods 根据 IDL 生成，
+// IDL 新增方法后执行 make methods 重新生成；没有登记的方法 NewThriftServer 直接返回错误
+var thriftMethods = newThriftMethods(map[string]generatedMethods{
+	"UserService":          user_service.UserServiceMethods,
+	"GiftService":          gift_service.GiftServiceMethods,
+	health.ServiceName:     healthgen.HealthMethods,
+	reflection.ServiceName: reflectiongen.ReflectionMethods,
+})
+
+// newThriftMethods 按多路处理器中注册的服务名合并各服务生成的方法表
+func newThriftMethods(services map[string]generatedMethods) map[string]thriftMethod {
+	methods := make(map[string]thriftMethod)
+	for service, generated := range services {
+		for name, m := range generated {
+			methods[service+thrift.MULTIPLEXED_SEPARATOR+name] = thriftMethod{args: m.Args, result: m.Result}
+		}
+	}
+	return methods
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"aboveThriftRPC/internal/pkg/metrics"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// metricsProcessor 按服务与方法记录请求数、耗时与处理中的请求数，位于最外层，包含排队与超时的时间
+func metricsProcessor() thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		service, method, ok := strings.Cut(name, thrift.MULTIPLEXED_SEPARATOR)
+		if !ok {
+			service, method = "", name
+		}
+		inFlight := metrics.ServerInFlight.WithLabelValues(service, method)
+		duration := metrics.ServerDuration.WithLabelValues(service, method)
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				inFlight.Inc()
+				defer inFlight.Dec()
+				start := time.Now()
+				ok, exc := next.Process(ctx, seqID, in, out)
+				duration.Observe(time.Since(start).Seconds())
+				metrics.ServerRequests.WithLabelValues(service, method, metrics.Code(exc)).Inc()
+				return ok, exc
+			},
+		}
+	}
+}
+
+// countedConn 关闭时记录连接关闭数，多次关闭只记录一次
+type countedConn struct {
+	thrift.TTransport
+	once sync.Once
+}
+
+func newCountedConn(client thrift.TTransport) *countedConn {
+	metrics.ServerConnectionsAccepted.Inc()
+	return &countedConn{TTransport: client}
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(metrics.ServerConnectionsClosed.Inc)
+	return c.TTransport.Close()
+}
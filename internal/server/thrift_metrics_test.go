@@ -0,0 +1,68 @@
+package server
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/metrics"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestThriftMetrics 测试服务端按服务与方法记录请求与连接指标，并通过 /metrics 输出
+func TestThriftMetrics(t *testing.T) {
+	ts, addr := startTestServerWith(t, &conf.Server_Thrift{}, &thrifttest.UserService{}, ThriftMiddleware(recovery.Recovery()))
+	hs := httptest.NewServer(NewHTTPServer(&conf.Server{Http: &conf.Server_HTTP{}}, ts, &thrifttest.UserService{}, &thrifttest.GiftService{}, log.DefaultLogger))
+	defer hs.Close()
+
+	ok := metrics.ServerRequests.WithLabelValues("UserService", "echoData", metrics.CodeOK)
+	failed := metrics.ServerRequests.WithLabelValues("UserService", "echoData", "INTERNAL_ERROR")
+	accepted, closed := testutil.ToFloat64(metrics.ServerConnectionsAccepted), testutil.ToFloat64(metrics.ServerConnectionsClosed)
+	okBefore, failedBefore := testutil.ToFloat64(ok), testutil.ToFloat64(failed)
+
+	client, trans := dialUserClient(t, addr)
+	if err := callEcho(client, "hello"); err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+	if err := callEcho(client, "panic"); err == nil {
+		t.Fatal("期望 panic 的请求失败")
+	}
+	if got := testutil.ToFloat64(ok) - okBefore; got != 1 {
+		t.Errorf("期望成功请求数增加 1, 实际 %v", got)
+	}
+	if got := testutil.ToFloat64(failed) - failedBefore; got != 1 {
+		t.Errorf("期望 INTERNAL_ERROR 请求数增加 1, 实际 %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.ServerInFlight.WithLabelValues("UserService", "echoData")); got != 0 {
+		t.Errorf("请求完成后期望没有处理中的请求, 实际 %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.ServerConnectionsAccepted) - accepted; got < 1 {
+		t.Errorf("期望接受的连接数增加, 实际 %v", got)
+	}
+
+	// 客户端断开后服务端关闭连接
+	trans.Close()
+	for i := 0; i < 50 && testutil.ToFloat64(metrics.ServerConnectionsClosed) == closed; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if testutil.ToFloat64(metrics.ServerConnectionsClosed) == closed {
+		t.Error("期望关闭的连接数增加")
+	}
+
+	code, body := doJSON(t, "GET", hs.URL+"/metrics", "")
+	for _, want := range []string{
+		`thrift_server_requests_total{code="OK",method="echoData",service="UserService"}`,
+		`thrift_server_request_duration_seconds_bucket{method="echoData",service="UserService",le="+Inf"}`,
+		"thrift_server_connections_accepted_total",
+	} {
+		if code != 200 || !strings.Contains(body, want) {
+			t.Fatalf("/metrics 返回 %d, 缺少 %s", code, want)
+		}
+	}
+}
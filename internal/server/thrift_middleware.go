@@ -0,0 +1,249 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"aboveThriftRPC/internal/pkg/health"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/middleware"
+)
+
+// ThriftServerOption thrift 服务端选项
+type ThriftServerOption func(*ThriftServer)
+
+// ThriftMiddleware 追加包裹每个 thrift 处理函数的 kratos 中间件，按添加顺序由外到内执行
+//
+// 中间件拿到的 req 为生成的参数结构体（如 *gift_service.GiftServiceSendGiftArgs），reply 为结果结构体，
+// 与 HTTP 网关一致，validate、logging 等依赖请求内容的中间件可以直接使用。
+func ThriftMiddleware(m ...middleware.Middleware) ThriftServerOption {
+	return func(s *ThriftServer) {
+		s.middleware = append(s.middleware, m...)
+	}
+}
+
+// ThriftHealth 使用 h 报告服务状态，默认使用不带依赖检查的健康检查服务
+func ThriftHealth(h *health.Server) ThriftServerOption {
+	return func(s *ThriftServer) {
+		s.health = h
+	}
+}
+
+// argsProtocol 记录请求参数是否已经读取完毕
+type argsProtocol struct {
+	thrift.TProtocol
+	read bool
+}
+
+func (p *argsProtocol) ReadMessageEnd(ctx context.Context) error {
+	p.read = true
+	return p.TProtocol.ReadMessageEnd(ctx)
+}
+
+// replyProtocol 记录响应是否已经开始写入
+type replyProtocol struct {
+	thrift.TProtocol
+	written bool
+}
+
+func (p *replyProtocol) WriteMessageBegin(ctx context.Context, name string, typeID thrift.TMessageType, seqID int32) error {
+	p.written = true
+	return p.TProtocol.WriteMessageBegin(ctx, name, typeID, seqID)
+}
+
+// methodName 从多路处理器的函数名（Service:method）中取出方法名
+func methodName(name string) string {
+	if i := strings.Index(name, thrift.MULTIPLEXED_SEPARATOR); i >= 0 {
+		return name[i+len(thrift.MULTIPLEXED_SEPARATOR):]
+	}
+	return name
+}
+
+// middlewareProcessor 将 kratos 中间件链转换为 thrift 处理函数中间件
+//
+// 参数在中间件链之前按 methods 解码，作为 req 传给中间件；最内层的 handler 在内存中执行生成的处理函数，
+// 返回解码后的结果结构体，响应在中间件链返回之后才写回客户端。中间件返回错误（如限流、参数校验）
+// 或处理函数返回错误时写回 TApplicationException，保证客户端能收到结果且连接可以继续使用。
+// 处理函数执行之后的错误按 executedError 转换，不会写回 LOADSHEDDING。
+func middlewareProcessor(m middleware.Middleware, methods map[string]thriftMethod) thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		method := methodName(name)
+		desc := methods[name]
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				args := desc.args()
+				if err := args.Read(ctx, in); err != nil {
+					in.ReadMessageEnd(ctx)
+					x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err.Error())
+					writeMessage(ctx, out, method, thrift.EXCEPTION, seqID, x)
+					return false, thrift.WrapTException(err)
+				}
+				if err := in.ReadMessageEnd(ctx); err != nil {
+					return false, thrift.WrapTException(err)
+				}
+
+				// processed 为 false 时生成的处理函数没有产生响应，如客户端断开后放弃的请求
+				processed := true
+				// executed 为 true 时处理函数已经执行
+				executed := false
+				var exc thrift.TException
+				h := m(func(ctx context.Context, req any) (any, error) {
+					args, ok := req.(thrift.TStruct)
+					if !ok {
+						return nil, fmt.Errorf("unexpected request type %T", req)
+					}
+					executed = true
+					var result thrift.TStruct
+					processed, exc, result = call(ctx, next, seqID, args, desc.result(), in.Transport())
+					if result == nil {
+						return nil, exc
+					}
+					// IDL 声明的异常在结果结构体中，exc 只交给外层的访问日志与指标
+					return result, nil
+				})
+				reply, err := h(ctx, args)
+				if !processed {
+					return false, exc
+				}
+				if err == nil {
+					if result, ok := reply.(thrift.TStruct); ok && result != nil {
+						if werr := writeMessage(ctx, out, method, thrift.REPLY, seqID, result); werr != nil {
+							return false, werr
+						}
+						return true, exc
+					}
+					err = fmt.Errorf("unexpected reply type %T", reply)
+				}
+				werr := err
+				if executed {
+					werr = executedError(method, err)
+				}
+				ok, x := writeException(ctx, method, seqID, &argsProtocol{TProtocol: in, read: true}, out, werr)
+				if ok && exc != nil && err == error(exc) {
+					// 处理函数返回的错误原样交给外层的访问日志与指标，与生成的处理函数一致
+					return true, exc
+				}
+				return ok, x
+			},
+		}
+	}
+}
+
+// executedError 处理函数执行之后的错误，LOADSHEDDING 改为 INTERNAL_ERROR
+//
+// 客户端把 LOADSHEDDING 当作请求没有被处理，总是重试。处理函数返回的 429、503（如 Redis 连接错误）
+// 或中间件在处理函数返回之后产生的这类错误都发生在请求已经处理之后，非幂等的调用重试会重复执行。
+func executedError(method string, err error) error {
+	var exc thrift.TApplicationException
+	if !errors.As(err, &exc) {
+		exc, _ = thriftx.ToApplicationException(err)
+	}
+	if exc != nil && exc.TypeId() == thriftx.LOADSHEDDING {
+		return thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing "+method+": "+err.Error())
+	}
+	return err
+}
+
+// call 执行生成的处理函数，参数与响应都经过内存中的 binary 协议
+//
+// 响应为结果结构体时返回 result，exc 为 IDL 声明的异常或 nil；响应为异常时 result 为 nil，exc 为处理函数返回的错误。
+// conn 为连接的传输层，生成的处理函数通过它检查客户端是否已经断开。
+func call(ctx context.Context, next thrift.TProcessorFunction, seqID int32, args, result thrift.TStruct, conn thrift.TTransport) (bool, thrift.TException, thrift.TStruct) {
+	in := newLocalProtocol(conn)
+	if err := args.Write(ctx, in); err != nil {
+		return true, thrift.WrapTException(err), nil
+	}
+	out := newLocalProtocol(conn)
+	ok, exc := next.Process(ctx, seqID, in, out)
+	if !ok {
+		return false, exc, nil
+	}
+	_, typ, _, err := out.ReadMessageBegin(ctx)
+	if err != nil {
+		return true, thrift.WrapTException(err), nil
+	}
+	if typ == thrift.EXCEPTION {
+		x := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "")
+		if err := x.Read(ctx, out); err != nil {
+			return true, thrift.WrapTException(err), nil
+		}
+		if exc == nil {
+			exc = x
+		}
+		return true, exc, nil
+	}
+	if err := result.Read(ctx, out); err != nil {
+		return true, thrift.WrapTException(err), nil
+	}
+	return true, exc, result
+}
+
+// localProtocol 内存中的 binary 协议，Transport 返回连接的传输层
+type localProtocol struct {
+	*thrift.TBinaryProtocol
+	conn thrift.TTransport
+}
+
+func newLocalProtocol(conn thrift.TTransport) *localProtocol {
+	return &localProtocol{TBinaryProtocol: thrift.NewTBinaryProtocolConf(thrift.NewTMemoryBuffer(), nil), conn: conn}
+}
+
+func (p *localProtocol) Transport() thrift.TTransport {
+	return p.conn
+}
+
+// writeMessage 写入一条完整的消息
+func writeMessage(ctx context.Context, out thrift.TProtocol, method string, typ thrift.TMessageType, seqID int32, body thrift.TStruct) thrift.TException {
+	if err := out.WriteMessageBegin(ctx, method, typ, seqID); err != nil {
+		return thrift.WrapTException(err)
+	}
+	if err := body.Write(ctx, out); err != nil {
+		return thrift.WrapTException(err)
+	}
+	if err := out.WriteMessageEnd(ctx); err != nil {
+		return thrift.WrapTException(err)
+	}
+	if err := out.Flush(ctx); err != nil {
+		return thrift.WrapTException(err)
+	}
+	return nil
+}
+
+// writeException 跳过未读取的参数并向客户端写回异常
+//
+// kratos 错误按 thriftx.ToApplicationException 转换，其余错误为 INTERNAL_ERROR。
+// 只有处理函数执行之前的拒绝（并发上限、限流、熔断）可以写回 LOADSHEDDING，见 executedError。
+func writeException(ctx context.Context, method string, seqID int32, args *argsProtocol, out thrift.TProtocol, err error) (bool, thrift.TException) {
+	if !args.read {
+		if err := args.Skip(ctx, thrift.STRUCT); err != nil {
+			return false, thrift.WrapTException(err)
+		}
+		if err := args.ReadMessageEnd(ctx); err != nil {
+			return false, thrift.WrapTException(err)
+		}
+	}
+	var exc thrift.TApplicationException
+	if !errors.As(err, &exc) {
+		var ok bool
+		if exc, ok = thriftx.ToApplicationException(err); !ok {
+			exc = thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing "+method+": "+err.Error())
+		}
+	}
+	if err2 := out.WriteMessageBegin(ctx, method, thrift.EXCEPTION, seqID); err2 != nil {
+		return false, thrift.WrapTException(err2)
+	}
+	if err2 := exc.Write(ctx, out); err2 != nil {
+		return false, thrift.WrapTException(err2)
+	}
+	if err2 := out.WriteMessageEnd(ctx); err2 != nil {
+		return false, thrift.WrapTException(err2)
+	}
+	if err2 := out.Flush(ctx); err2 != nil {
+		return false, thrift.WrapTException(err2)
+	}
+	return true, exc
+}
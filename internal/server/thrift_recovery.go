@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"strings"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/sirupsen/logrus"
+)
+
+// errPanic 写回客户端的异常不包含 panic 的具体内容
+var errPanic = errors.New("panic")
+
+// recoveryProcessor 拦截每次调用中的 panic，记录方法名与调用栈后向客户端写回 INTERNAL_ERROR 异常
+//
+// 位于超时控制之内，与处理函数在同一个协程中，kratos 中间件与处理函数的 panic 都会被拦截。
+// 参数已经读完且响应还没开始写入时连接可以继续使用，否则写回异常后关闭连接。
+func recoveryProcessor() thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		operation := strings.Replace(name, thrift.MULTIPLEXED_SEPARATOR, ".", 1)
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (ok bool, exc thrift.TException) {
+				args := &argsProtocol{TProtocol: in}
+				reply := &replyProtocol{TProtocol: out}
+				defer func() {
+					r := recover()
+					if r == nil {
+						return
+					}
+					logrus.Errorf("thrift server panic processing %s: %v\n%s", operation, r, debug.Stack())
+					switch {
+					case reply.written:
+						// 响应只写了一部分，只能关闭连接
+						ok, exc = false, thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing "+methodName(name)+": panic")
+					case !args.read:
+						// 参数读到一半，连接上剩余的数据无法跳过
+						_, exc = writeException(ctx, methodName(name), seqID, &argsProtocol{TProtocol: in, read: true}, out, errPanic)
+						ok = false
+					default:
+						ok, exc = writeException(ctx, methodName(name), seqID, args, out, errPanic)
+					}
+				}()
+				return next.Process(ctx, seqID, args, reply)
+			},
+		}
+	}
+}
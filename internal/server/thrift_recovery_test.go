@@ -0,0 +1,62 @@
+package server
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/internal/conf"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// TestThriftRecovery 测试处理函数 panic 时返回 INTERNAL_ERROR，连接继续可用，日志包含方法名与调用栈
+func TestThriftRecovery(t *testing.T) {
+	cases := []struct {
+		name string
+		c    *conf.Server_Thrift
+	}{
+		{"default", &conf.Server_Thrift{}},
+		// 配置超时后处理函数在单独的协程中执行
+		{"timeout", &conf.Server_Thrift{Timeout: durationpb.New(time.Second)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			hook := test.NewGlobal()
+			t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+			addr := startTestServer(t, c.c)
+			client, _ := dialUserClient(t, addr)
+
+			err := callEcho(client, "panic")
+			var appErr thrift.TApplicationException
+			if !stderrors.As(err, &appErr) || appErr.TypeId() != thrift.INTERNAL_ERROR {
+				t.Fatalf("期望返回 INTERNAL_ERROR, 实际: %v", err)
+			}
+			if strings.Contains(err.Error(), "echo panic") {
+				t.Errorf("异常中不应包含 panic 的内容: %v", err)
+			}
+			if err := callEcho(client, "hello"); err != nil {
+				t.Fatalf("panic 后连接不可用: %v", err)
+			}
+
+			var logged *logrus.Entry
+			for _, e := range hook.AllEntries() {
+				if e.Level == logrus.ErrorLevel && strings.Contains(e.Message, "panic") {
+					logged = e
+				}
+			}
+			if logged == nil {
+				t.Fatal("期望记录 panic 日志")
+			}
+			for _, want := range []string{"UserService.echoData", "echo panic", "thrifttest.go"} {
+				if !strings.Contains(logged.Message, want) {
+					t.Errorf("panic 日志缺少 %q: %s", want, logged.Message)
+				}
+			}
+		})
+	}
+}
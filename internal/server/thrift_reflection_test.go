@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/reflection"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// TestThriftReflection 测试通过内置的反射服务发现服务端注册的服务与方法签名
+func TestThriftReflection(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{})
+	socket := thrift.NewTSocketConf(addr, &thrift.TConfiguration{ConnectTimeout: time.Second, SocketTimeout: 5 * time.Second})
+	transport := thrift.NewTBufferedTransport(socket, 2048)
+	if err := transport.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	defer transport.Close()
+	client := reflection.NewClientProtocol(thrift.NewTBinaryProtocolConf(transport, nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	services, err := reflection.Discover(ctx, client)
+	if err != nil {
+		t.Fatalf("查询服务失败: %v", err)
+	}
+	signatures := map[string]string{}
+	for _, svc := range services {
+		for _, m := range svc.Methods {
+			signatures[svc.Name+"."+m.Name] = m.Signature
+		}
+	}
+	want := map[string]string{
+		"UserService.echoData":             "EchoResponse echoData(1: binary clientData, 2: User user)",
+		"GiftService.GetGiftsBySender":     "list<Gift> GetGiftsBySender(1: i64 senderId)",
+		"Reflection.ListServices":          "list<string> ListServices()",
+		"Reflection.DescribeService":       "ServiceInfo DescribeService(1: string service) throws (1: ReflectionException err)",
+		"GiftService.GetSendersInLastWeek": "list<i64> GetSendersInLastWeek()",
+	}
+	for method, sig := range want {
+		if signatures[method] != sig {
+			t.Errorf("%s 的签名为 %q, 期望 %q", method, signatures[method], sig)
+		}
+	}
+
+	src, err := client.GetFileSource(ctx, "gift_service.thrift")
+	if err != nil || !strings.Contains(src, "service GiftService") {
+		t.Fatalf("获取 IDL 源码失败: %v", err)
+	}
+	// IDL 异常不影响连接继续使用
+	if _, err := client.DescribeService(ctx, "Missing"); err == nil {
+		t.Fatal("期望查询不存在的服务失败")
+	}
+	if _, err := client.ListServices(ctx); err != nil {
+		t.Fatalf("异常后继续调用失败: %v", err)
+	}
+}
@@ -0,0 +1,401 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/sirupsen/logrus"
+)
+
+// 超出连接数或请求数上限时的策略
+const (
+	// OverloadReject 直接拒绝：新连接被关闭，新请求收到 LOADSHEDDING 异常
+	OverloadReject = "reject"
+	// OverloadQueue 排队等待：新连接等待空闲 worker，新请求等待空闲的处理名额
+	OverloadQueue = "queue"
+)
+
+// thriftServe 有界的 thrift 服务循环，替代 thrift.TSimpleServer 每个连接一个协程且没有上限的模型
+//
+// maxConns 大于 0 时由固定数量的 worker 处理连接，超出的连接进入长度为 backlog 的队列。
+// 队列已满时 reject 策略直接关闭新连接，queue 策略暂停 accept，由内核的监听队列继续缓冲。
+//
+// worker 按连接调度而不是按请求调度：一个连接从开始处理到客户端关闭一直占用同一个 worker，
+// 连接上没有请求时也不会释放。连接池的长连接因此会占满全部 worker，队列中的连接要等到
+// 某个长连接关闭才会被处理，queue 策略下队列满后 accept 也随之停止。max_connections
+// 需要不小于所有客户端连接池 maxActive 之和，或者让客户端的 idleTimeout 尽快关闭空闲连接。
+type thriftServe struct {
+	processor        thrift.TProcessor
+	serverTransport  thrift.TServerTransport
+	transportFactory thrift.TTransportFactory
+	protocolFactory  thrift.TProtocolFactory
+	maxConns         int
+	policy           string
+
+	backlog chan thrift.TTransport
+	quit    chan struct{}
+	wg      sync.WaitGroup
+
+	mu        sync.Mutex
+	listening bool
+	closed    bool
+	conns     map[*serverConn]struct{}
+}
+
+func newThriftServe(processor thrift.TProcessor, serverTransport thrift.TServerTransport, transportFactory thrift.TTransportFactory, protocolFactory thrift.TProtocolFactory, maxConns, backlog int, policy string) *thriftServe {
+	return &thriftServe{
+		processor:        processor,
+		serverTransport:  serverTransport,
+		transportFactory: transportFactory,
+		protocolFactory:  protocolFactory,
+		maxConns:         maxConns,
+		policy:           policy,
+		backlog:          make(chan thrift.TTransport, backlog),
+		quit:             make(chan struct{}),
+		conns:            make(map[*serverConn]struct{}),
+	}
+}
+
+// Listen 监听地址并启动 worker，监听失败时直接返回错误，重复调用不会重复监听
+func (s *thriftServe) Listen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || s.listening {
+		return nil
+	}
+	if err := s.serverTransport.Listen(); err != nil {
+		return err
+	}
+	s.listening = true
+	s.wg.Add(s.maxConns)
+	for i := 0; i < s.maxConns; i++ {
+		go s.worker()
+	}
+	return nil
+}
+
+// Addr 返回实际监听的地址，未监听时返回配置的地址
+func (s *thriftServe) Addr() net.Addr {
+	if a, ok := s.serverTransport.(interface{ Addr() net.Addr }); ok {
+		return a.Addr()
+	}
+	return nil
+}
+
+// Serve 循环接受连接，直到 Stop 被调用
+func (s *thriftServe) Serve() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.wg.Add(1)
+	s.mu.Unlock()
+	defer s.wg.Done()
+
+	for {
+		client, err := s.serverTransport.Accept()
+		if s.isClosed() {
+			if client != nil {
+				client.Close()
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.dispatch(newCountedConn(client))
+	}
+}
+
+// dispatch 将新连接交给 worker，没有连接数上限时为每个连接启动一个协程
+func (s *thriftServe) dispatch(client thrift.TTransport) {
+	if s.maxConns <= 0 {
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			client.Close()
+			return
+		}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go func() {
+			defer s.wg.Done()
+			s.handle(client)
+		}()
+		return
+	}
+
+	select {
+	case s.backlog <- client:
+		return
+	default:
+	}
+	if s.policy == OverloadQueue {
+		select {
+		case s.backlog <- client:
+		case <-s.quit:
+			client.Close()
+		}
+		return
+	}
+	logrus.Warnf("thrift server reached max connections %d, rejecting connection", s.maxConns)
+	client.Close()
+}
+
+func (s *thriftServe) worker() {
+	defer s.wg.Done()
+	for {
+		select {
+		case client := <-s.backlog:
+			s.handle(client)
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// handle 处理单个连接上的全部请求
+func (s *thriftServe) handle(client thrift.TTransport) {
+	conn := &serverConn{TTransport: client}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		client.Close()
+		return
+	}
+	s.conns[conn] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	if err := s.processRequests(conn); err != nil {
+		logrus.Errorf("thrift server error processing request: %v", err)
+	}
+}
+
+// processRequests 与 thrift.TSimpleServer 的处理逻辑一致，停止后处理完当前请求即退出
+func (s *thriftServe) processRequests(conn *serverConn) (err error) {
+	defer func() {
+		err = treatEOFErrorsAsNil(err)
+	}()
+
+	trans, err := s.transportFactory.GetTransport(conn)
+	if err != nil {
+		return err
+	}
+	defer trans.Close()
+	protocol := s.protocolFactory.GetProtocol(trans)
+	// THeader 协议需要在同一个实例上读写，才能按请求的格式写回响应
+	headerProtocol, _ := protocol.(*thrift.THeaderProtocol)
+	peer := peerAddr(conn.TTransport)
+
+	for !s.isClosed() {
+		conn.meter.reset()
+		ctx := withPeer(withSizeMeter(context.Background(), &conn.meter), peer)
+		ctx = thrift.SetResponseHelper(ctx, thrift.TResponseHelper{
+			THeaderResponseHelper: thrift.NewTHeaderResponseHelper(protocol),
+		})
+		if headerProtocol != nil {
+			// 先读取帧才能拿到请求头，之后处理器再次调用 ReadFrame 不会重复读取
+			if err := headerProtocol.ReadFrame(ctx); err != nil {
+				return err
+			}
+			ctx = thrift.AddReadTHeaderToContext(ctx, headerProtocol.GetReadHeaders())
+		}
+
+		ok, err := s.processor.Process(ctx, protocol, protocol)
+		conn.setIdle()
+		if errors.Is(err, thrift.ErrAbandonRequest) {
+			if err := conn.Close(); !errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			return nil
+		}
+		if err != nil && errors.As(err, new(thrift.TTransportException)) {
+			return err
+		}
+		var tae thrift.TApplicationException
+		if errors.As(err, &tae) && tae.TypeId() == thrift.UNKNOWN_METHOD {
+			continue
+		}
+		if !ok {
+			break
+		}
+	}
+	return nil
+}
+
+// serverConn 记录连接是否正在处理请求，停止时只直接关闭空闲的连接
+//
+// 读到请求数据时标记为处理中，处理函数返回后恢复空闲。meter 统计当前请求读写的字节数。
+// closed 与 active 由同一把锁保护，已经被 closeIfIdle 关闭的连接读到的数据直接丢弃。
+type serverConn struct {
+	thrift.TTransport
+	mu     sync.Mutex
+	active bool
+	closed bool
+	meter  sizeMeter
+}
+
+func (c *serverConn) Read(p []byte) (int, error) {
+	n, err := c.TTransport.Read(p)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return 0, thrift.NewTTransportException(thrift.NOT_OPEN, "connection closed by server")
+	}
+	if n > 0 {
+		c.active = true
+	}
+	c.mu.Unlock()
+	if merr := c.meter.count(n); merr != nil {
+		return 0, merr
+	}
+	return n, err
+}
+
+func (c *serverConn) Write(p []byte) (int, error) {
+	n, err := c.TTransport.Write(p)
+	c.meter.countWritten(n)
+	return n, err
+}
+
+func (c *serverConn) setIdle() {
+	c.mu.Lock()
+	c.active = false
+	c.mu.Unlock()
+}
+
+// closeIfIdle 关闭空闲的连接，处理中的连接在当前请求完成后由处理循环关闭
+func (c *serverConn) closeIfIdle() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.active {
+		c.closed = true
+		c.TTransport.Close()
+	}
+}
+
+// treatEOFErrorsAsNil 客户端断开连接不视为错误
+func treatEOFErrorsAsNil(err error) error {
+	if err == nil || errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	var te thrift.TTransportException
+	if errors.As(err, &te) && (te.TypeId() == thrift.END_OF_FILE || te.TypeId() == thrift.NOT_OPEN) {
+		return nil
+	}
+	return err
+}
+
+func (s *thriftServe) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// Stop 停止接受连接并关闭空闲连接，等待处理中的请求完成
+//
+// ctx 到期时强制关闭剩余的连接并返回 ctx 的错误，不再等待仍在执行的处理函数。
+func (s *thriftServe) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	close(s.quit)
+	for conn := range s.conns {
+		conn.closeIfIdle()
+	}
+	s.mu.Unlock()
+
+	// Interrupt 让阻塞的 Accept 返回，Close 释放监听
+	s.serverTransport.Interrupt()
+	err := s.serverTransport.Close()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.mu.Lock()
+		logrus.Warnf("thrift server drain timeout, closing %d connections", len(s.conns))
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		err = ctx.Err()
+	}
+
+	// 关闭还在队列中等待的连接
+	for {
+		select {
+		case client := <-s.backlog:
+			client.Close()
+		default:
+			return err
+		}
+	}
+}
+
+// limitProcessor 限制同时处理的请求数，sem 的容量即上限
+//
+// reject 策略下没有空闲名额时立即返回 LOADSHEDDING 异常；queue 策略下等待空闲名额，
+// timeout 大于 0 时等待超时同样返回 LOADSHEDDING 异常。连接保持可用。
+func limitProcessor(sem chan struct{}, policy string, timeout time.Duration) thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				if !acquire(ctx, sem, policy, timeout) {
+					logrus.Warnf("thrift server reached max in-flight requests %d, rejecting %s", cap(sem), name)
+					exc := thriftx.NewLoadSheddingException("server overloaded: too many in-flight requests")
+					return writeException(ctx, methodName(name), seqID, &argsProtocol{TProtocol: in}, out, exc)
+				}
+				defer func() { <-sem }()
+				return next.Process(ctx, seqID, in, out)
+			},
+		}
+	}
+}
+
+func acquire(ctx context.Context, sem chan struct{}, policy string, timeout time.Duration) bool {
+	select {
+	case sem <- struct{}{}:
+		return true
+	default:
+	}
+	if policy != OverloadQueue {
+		return false
+	}
+	// 请求超时后不再等待
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-expired:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
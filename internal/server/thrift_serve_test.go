@@ -0,0 +1,357 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// blockingUserService clientData 为 block 时阻塞，直到 release 被关闭
+type blockingUserService struct {
+	thrifttest.UserService
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingUserService() *blockingUserService {
+	return &blockingUserService{started: make(chan struct{}, 16), release: make(chan struct{})}
+}
+
+func (s *blockingUserService) EchoData(ctx context.Context, clientData []byte, user *user_service.User) (*user_service.EchoResponse, error) {
+	if string(clientData) == "block" {
+		s.started <- struct{}{}
+		<-s.release
+	}
+	return s.UserService.EchoData(ctx, clientData, user)
+}
+
+// dialUserClient 创建 binary 协议的 UserService 客户端，并返回底层连接用于主动关闭
+func dialUserClient(t *testing.T, addr string) (*user_service.UserServiceClient, thrift.TTransport) {
+	t.Helper()
+	socket := thrift.NewTSocketConf(addr, &thrift.TConfiguration{ConnectTimeout: time.Second, SocketTimeout: 5 * time.Second})
+	transport := thrift.NewTBufferedTransport(socket, 2048)
+	if err := transport.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	t.Cleanup(func() {
+		transport.Close()
+	})
+	protocol := thrift.NewTMultiplexedProtocol(thrift.NewTBinaryProtocolConf(transport, nil), "UserService")
+	return user_service.NewUserServiceClient(thrift.NewTStandardClient(protocol, protocol)), transport
+}
+
+// dialServed 重试直到新连接被 worker 处理，用于等待之前的连接（包括启动时的探测连接）释放 worker
+func dialServed(t *testing.T, addr string) (*user_service.UserServiceClient, thrift.TTransport) {
+	t.Helper()
+	var err error
+	for i := 0; i < 50; i++ {
+		client, trans := dialUserClient(t, addr)
+		if err = callEcho(client, "hello"); err == nil {
+			return client, trans
+		}
+		trans.Close()
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("连接一直未被处理: %v", err)
+	return nil, nil
+}
+
+func callEcho(client *user_service.UserServiceClient, data string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.EchoData(ctx, []byte(data), &user_service.User{ID: 1})
+	return err
+}
+
+// TestThriftMaxConnectionsReject 测试连接数达到上限后 reject 策略直接关闭新连接
+func TestThriftMaxConnectionsReject(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{MaxConnections: 1})
+
+	_, firstTrans := dialServed(t, addr)
+	second, _ := dialUserClient(t, addr)
+	if err := callEcho(second, "second"); err == nil {
+		t.Fatal("期望超出连接数上限的连接被拒绝")
+	}
+
+	// 第一个连接断开后 worker 空闲，新连接可以正常调用
+	firstTrans.Close()
+	dialServed(t, addr)
+}
+
+// TestThriftMaxConnectionsQueue 测试连接数达到上限后 queue 策略让新连接排队等待
+func TestThriftMaxConnectionsQueue(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{MaxConnections: 1, OverloadPolicy: OverloadQueue})
+
+	_, firstTrans := dialServed(t, addr)
+	second, _ := dialUserClient(t, addr)
+	done := make(chan error, 1)
+	go func() {
+		done <- callEcho(second, "second")
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("期望第二个连接排队等待, 实际返回: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	firstTrans.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("排队的连接调用失败: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("排队的连接未被处理")
+	}
+}
+
+// TestThriftMaxConnectionsIdleHolds 测试 worker 按连接调度：没有请求的空闲连接仍然占用 worker，
+// 排队的连接直到它关闭才被处理，queue 策略下队列满后 accept 停止
+func TestThriftMaxConnectionsIdleHolds(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{MaxConnections: 1, AcceptBacklog: 1, OverloadPolicy: OverloadQueue})
+
+	first, firstTrans := dialServed(t, addr)
+	if err := callEcho(first, "first"); err != nil {
+		t.Fatalf("第一个连接调用失败: %v", err)
+	}
+
+	// 第二个连接进入队列，第三个连接阻塞在 accept 之后
+	done := make(chan error, 2)
+	for _, data := range []string{"second", "third"} {
+		client, _ := dialUserClient(t, addr)
+		go func(data string) {
+			done <- callEcho(client, data)
+		}(data)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("期望空闲连接占用 worker 时新连接等待, 实际返回: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// 排队的连接同样占用 worker，只关闭第一个连接时第三个连接仍在等待
+	firstTrans.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("排队的连接调用失败: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("排队的连接未被处理")
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("期望第三个连接继续等待, 实际返回: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestServerConnReadAfterClose 测试 closeIfIdle 关闭连接后读到的数据被丢弃，不会标记为处理中
+func TestServerConnReadAfterClose(t *testing.T) {
+	buf := thrift.NewTMemoryBuffer()
+	buf.WriteString("request")
+	conn := &serverConn{TTransport: buf}
+	conn.closeIfIdle()
+
+	n, err := conn.Read(make([]byte, 16))
+	var te thrift.TTransportException
+	if n != 0 || !stderrors.As(err, &te) || te.TypeId() != thrift.NOT_OPEN {
+		t.Fatalf("期望关闭后读取返回 NOT_OPEN, 实际: n=%d err=%v", n, err)
+	}
+	if conn.active {
+		t.Fatal("关闭后的连接不应标记为处理中")
+	}
+}
+
+// TestThriftMaxInFlightReject 测试请求数达到上限后返回 LOADSHEDDING 异常且连接保持可用
+func TestThriftMaxInFlightReject(t *testing.T) {
+	user := newBlockingUserService()
+	_, addr := startTestServerWith(t, &conf.Server_Thrift{MaxInFlight: 1}, user)
+
+	blocked, _ := dialUserClient(t, addr)
+	done := make(chan error, 1)
+	go func() {
+		done <- callEcho(blocked, "block")
+	}()
+	<-user.started
+
+	client, _ := dialUserClient(t, addr)
+	err := callEcho(client, "hello")
+	var exc thrift.TApplicationException
+	if !stderrors.As(err, &exc) || exc.TypeId() != thriftx.LOADSHEDDING {
+		t.Fatalf("期望收到 LOADSHEDDING 异常, 实际: %v", err)
+	}
+
+	close(user.release)
+	if err := <-done; err != nil {
+		t.Fatalf("阻塞的调用失败: %v", err)
+	}
+	if err := callEcho(client, "hello"); err != nil {
+		t.Fatalf("被拒绝后连接不可用: %v", err)
+	}
+}
+
+// TestThriftMaxInFlightQueue 测试请求数达到上限后 queue 策略排队等待，超过 queue_timeout 时拒绝
+func TestThriftMaxInFlightQueue(t *testing.T) {
+	user := newBlockingUserService()
+	_, addr := startTestServerWith(t, &conf.Server_Thrift{
+		MaxInFlight:    1,
+		OverloadPolicy: OverloadQueue,
+		QueueTimeout:   durationpb.New(100 * time.Millisecond),
+	}, user)
+
+	blocked, _ := dialUserClient(t, addr)
+	done := make(chan error, 1)
+	go func() {
+		done <- callEcho(blocked, "block")
+	}()
+	<-user.started
+
+	// 等待超过 queue_timeout 后被拒绝
+	client, _ := dialUserClient(t, addr)
+	err := callEcho(client, "hello")
+	var exc thrift.TApplicationException
+	if !stderrors.As(err, &exc) || exc.TypeId() != thriftx.LOADSHEDDING {
+		t.Fatalf("期望收到 LOADSHEDDING 异常, 实际: %v", err)
+	}
+
+	// 在 queue_timeout 内释放名额时排队的请求正常完成
+	queued := make(chan error, 1)
+	go func() {
+		queued <- callEcho(client, "queued")
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(user.release)
+	if err := <-done; err != nil {
+		t.Fatalf("阻塞的调用失败: %v", err)
+	}
+	if err := <-queued; err != nil {
+		t.Fatalf("排队的调用失败: %v", err)
+	}
+}
+
+// TestThriftUnknownOverloadPolicy 测试配置了不支持的过载策略时返回错误
+func TestThriftUnknownOverloadPolicy(t *testing.T) {
+	_, err := NewThriftServer(&conf.Server{Thrift: &conf.Server_Thrift{Addr: thrifttest.FreeAddr(t), OverloadPolicy: "drop"}}, &thrifttest.UserService{}, &thrifttest.GiftService{})
+	if err == nil {
+		t.Fatal("期望不支持的过载策略返回错误")
+	}
+}
+
+// TestThriftServerStartListenError 测试监听失败时 Start 直接返回错误
+func TestThriftServerStartListenError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("占用端口失败: %v", err)
+	}
+	defer l.Close()
+
+	srv, err := NewThriftServer(&conf.Server{Thrift: &conf.Server_Thrift{Addr: l.Addr().String()}}, &thrifttest.UserService{}, &thrifttest.GiftService{})
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Start(context.Background())
+	}()
+	select {
+	case err := <-errc:
+		if err == nil {
+			t.Fatal("期望端口被占用时 Start 返回错误")
+		}
+	case <-time.After(5 * time.Second):
+		srv.Stop(context.Background())
+		t.Fatal("端口被占用时 Start 没有返回")
+	}
+}
+
+// TestThriftServerGracefulStop 测试停止时不再接受新连接、关闭空闲连接，处理中的慢请求正常完成
+func TestThriftServerGracefulStop(t *testing.T) {
+	user := newBlockingUserService()
+	srv, addr := startTestServerWith(t, &conf.Server_Thrift{}, user)
+
+	idle, _ := dialUserClient(t, addr)
+	if err := callEcho(idle, "hello"); err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+	slow, _ := dialUserClient(t, addr)
+	done := make(chan error, 1)
+	go func() {
+		done <- callEcho(slow, "block")
+	}()
+	<-user.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- srv.Stop(ctx)
+	}()
+
+	// 停止后监听关闭，空闲连接被关闭
+	for i := 0; i < 50; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			break
+		}
+		conn.Close()
+		time.Sleep(20 * time.Millisecond)
+	}
+	if conn, err := net.Dial("tcp", addr); err == nil {
+		conn.Close()
+		t.Fatal("期望停止后不再接受新连接")
+	}
+	if err := callEcho(idle, "hello"); err == nil {
+		t.Fatal("期望停止后空闲连接被关闭")
+	}
+	select {
+	case err := <-stopped:
+		t.Fatalf("期望 Stop 等待处理中的请求, 实际返回: %v", err)
+	default:
+	}
+
+	close(user.release)
+	if err := <-done; err != nil {
+		t.Fatalf("处理中的请求未能完成: %v", err)
+	}
+	if err := <-stopped; err != nil {
+		t.Fatalf("Stop 返回错误: %v", err)
+	}
+}
+
+// TestThriftServerStopDeadline 测试 ctx 到期后强制关闭仍在处理请求的连接
+func TestThriftServerStopDeadline(t *testing.T) {
+	user := newBlockingUserService()
+	defer close(user.release)
+	srv, addr := startTestServerWith(t, &conf.Server_Thrift{}, user)
+
+	slow, _ := dialUserClient(t, addr)
+	done := make(chan error, 1)
+	go func() {
+		done <- callEcho(slow, "block")
+	}()
+	<-user.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := srv.Stop(ctx); !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("期望 Stop 返回 DeadlineExceeded, 实际: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Stop 未在 ctx 到期后返回, 耗时 %v", elapsed)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("期望被强制关闭的连接调用失败")
+	}
+}
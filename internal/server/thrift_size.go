@@ -0,0 +1,291 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"strings"
+	"sync"
+
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// defaultMaxMessageSize 默认的单条消息与单帧的最大字节数
+const defaultMaxMessageSize = 16 * 1024 * 1024 // 16 MB
+
+// errRequestTooLarge 请求读取的字节数超出方法的上限
+var errRequestTooLarge = errors.New("request too large")
+
+// sizeMeter 统计一次请求从连接上读取与写入的字节数，设置上限后超出的读取直接失败，不再继续缓冲请求体
+//
+// framed 传输层额外记录当前帧的长度，方法的上限可以在读取帧内容之前检查。
+type sizeMeter struct {
+	mu       sync.Mutex
+	n        int64
+	written  int64
+	limit    int64
+	exceeded bool
+	frame    int64
+	discard  func() error
+}
+
+type sizeMeterKey struct{}
+
+// withSizeMeter 把本次请求的 sizeMeter 放入 context，供 sizeLimitProcessor 设置上限
+func withSizeMeter(ctx context.Context, m *sizeMeter) context.Context {
+	return context.WithValue(ctx, sizeMeterKey{}, m)
+}
+
+// reset 开始读取新的请求前清零并取消上限
+func (m *sizeMeter) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.n, m.written, m.limit, m.exceeded, m.frame = 0, 0, 0, false, 0
+}
+
+// startFrame 记录 framed 传输层当前帧的长度，包含 4 字节帧头
+func (m *sizeMeter) startFrame(size int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.frame = size
+}
+
+// frameExceeds 当前帧的长度是否超出上限，非 framed 传输层总是返回 false
+func (m *sizeMeter) frameExceeds(limit int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.discard != nil && m.frame > limit
+}
+
+// count 记录读取的字节数，超出上限时返回 errRequestTooLarge
+func (m *sizeMeter) count(n int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.n += int64(n)
+	if m.limit > 0 && m.n > m.limit {
+		m.exceeded = true
+		return errRequestTooLarge
+	}
+	return nil
+}
+
+// setLimit 设置本次请求的上限，已经读取的字节数超出上限时返回 false
+func (m *sizeMeter) setLimit(limit int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limit = limit
+	if m.n > limit {
+		m.exceeded = true
+	}
+	return !m.exceeded
+}
+
+// countWritten 记录写回响应的字节数
+func (m *sizeMeter) countWritten(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.written += int64(n)
+}
+
+// sizes 返回本次请求读取与写入的字节数
+func (m *sizeMeter) sizes() (read, written int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.n, m.written
+}
+
+func (m *sizeMeter) isExceeded() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.exceeded
+}
+
+// meteredReader 经过 sizeMeter 计数的请求体，用于 thrift over http
+type meteredReader struct {
+	io.ReadCloser
+	meter *sizeMeter
+}
+
+func (r *meteredReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if cerr := r.meter.count(n); cerr != nil {
+		return 0, cerr
+	}
+	return n, err
+}
+
+// meteredWriter 经过 sizeMeter 计数的响应体，用于 thrift over http
+type meteredWriter struct {
+	nethttp.ResponseWriter
+	meter *sizeMeter
+}
+
+func (w *meteredWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.meter.countWritten(n)
+	return n, err
+}
+
+// sizeLimitProcessor 按方法限制请求的字节数，键为 Service.method
+//
+// 字节数按连接上实际读取的数据统计，包含消息头，超出上限时返回 REQUEST_TOO_LARGE：
+//   - framed 传输层在读取帧内容之前按帧头的长度检查，超出上限时不解析参数，边读边丢弃整帧，连接可以继续使用
+//   - header 传输层在分发前已经把整帧（不超过 max_frame_size）读入内存，超出上限时跳过参数，连接可以继续使用
+//   - buffered 传输层读取参数的过程中超出上限时不再继续读取，返回异常后关闭连接
+func sizeLimitProcessor(limits map[string]int64) thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		limit := limits[strings.Replace(name, thrift.MULTIPLEXED_SEPARATOR, ".", 1)]
+		if limit <= 0 {
+			return next
+		}
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				meter, ok := ctx.Value(sizeMeterKey{}).(*sizeMeter)
+				if !ok {
+					return next.Process(ctx, seqID, in, out)
+				}
+				exc := thriftx.NewRequestTooLargeException(fmt.Sprintf("request to %s exceeds %d bytes", methodName(name), limit))
+				if meter.frameExceeds(limit) {
+					// 先写回异常，客户端不必等帧内容发送完毕
+					ok, err := writeException(ctx, methodName(name), seqID, &argsProtocol{TProtocol: in, read: true}, out, exc)
+					if derr := meter.discard(); derr != nil {
+						return false, thrift.WrapTException(derr)
+					}
+					return ok, err
+				}
+				if !meter.setLimit(limit) {
+					// 参数已经全部在缓冲中时跳过参数，连接可以继续使用；否则不再读取连接
+					if in.Skip(ctx, thrift.STRUCT) == nil && in.ReadMessageEnd(ctx) == nil {
+						return writeException(ctx, methodName(name), seqID, &argsProtocol{TProtocol: in, read: true}, out, exc)
+					}
+					return rejectTooLarge(ctx, name, seqID, in, out, exc)
+				}
+				reply := &sizeReplyProtocol{TProtocol: out, meter: meter}
+				ok, err := next.Process(ctx, seqID, in, reply)
+				if !meter.isExceeded() {
+					return ok, err
+				}
+				// 生成的代码读取参数失败时写回的 PROTOCOL_ERROR 已被丢弃，改为写回 REQUEST_TOO_LARGE
+				return rejectTooLarge(ctx, name, seqID, in, out, exc)
+			},
+		}
+	}
+}
+
+// rejectTooLarge 请求体没有读完时写回异常，返回 false 关闭连接
+func rejectTooLarge(ctx context.Context, name string, seqID int32, in, out thrift.TProtocol, exc thrift.TApplicationException) (bool, thrift.TException) {
+	if _, err := writeException(ctx, methodName(name), seqID, &argsProtocol{TProtocol: in, read: true}, out, exc); err != exc {
+		return false, err
+	}
+	return false, exc
+}
+
+// sizeReplyProtocol 请求超出上限后丢弃处理函数写回的响应
+type sizeReplyProtocol struct {
+	thrift.TProtocol
+	meter *sizeMeter
+}
+
+func (p *sizeReplyProtocol) WriteMessageBegin(ctx context.Context, name string, typeID thrift.TMessageType, seqID int32) error {
+	if p.meter.isExceeded() {
+		p.TProtocol = thrift.NewTBinaryProtocolConf(thrift.NewTMemoryBuffer(), nil)
+	}
+	return p.TProtocol.WriteMessageBegin(ctx, name, typeID, seqID)
+}
+
+// framedTransportFactory 服务端的 framed 传输层，替代 thrift.TFramedTransportFactory
+type framedTransportFactory struct {
+	cfg *thrift.TConfiguration
+}
+
+func (f framedTransportFactory) GetTransport(trans thrift.TTransport) (thrift.TTransport, error) {
+	return newFramedTransport(trans, f.cfg), nil
+}
+
+// framedTransport 按帧头的长度边读边返回帧内容，不像 thrift.TFramedTransport 那样先把整帧读入内存，
+// 写入沿用 thrift.TFramedTransport
+//
+// 底层为 serverConn 时把帧长度记录到它的 sizeMeter，sizeLimitProcessor 据此在读取参数之前拒绝超限的帧。
+type framedTransport struct {
+	*thrift.TFramedTransport
+	reader       *bufio.Reader
+	maxFrameSize uint32
+	remaining    uint32
+	meter        *sizeMeter
+	header       [4]byte
+}
+
+func newFramedTransport(trans thrift.TTransport, cfg *thrift.TConfiguration) *framedTransport {
+	t := &framedTransport{
+		TFramedTransport: thrift.NewTFramedTransportConf(trans, cfg),
+		reader:           bufio.NewReader(trans),
+		maxFrameSize:     uint32(cfg.GetMaxFrameSize()),
+	}
+	if conn, ok := trans.(*serverConn); ok {
+		t.meter = &conn.meter
+		t.meter.discard = t.discardFrame
+	}
+	return t
+}
+
+// readHeader 读取下一帧的帧头，超出 max_frame_size 时返回错误，不读取帧内容
+func (p *framedTransport) readHeader() error {
+	for p.remaining == 0 {
+		if _, err := io.ReadFull(p.reader, p.header[:]); err != nil {
+			return thrift.NewTTransportExceptionFromError(err)
+		}
+		size := binary.BigEndian.Uint32(p.header[:])
+		if size > p.maxFrameSize {
+			return thrift.NewTTransportException(thrift.UNKNOWN_TRANSPORT_EXCEPTION, fmt.Sprintf("Incorrect frame size (%d)", size))
+		}
+		p.remaining = size
+		if p.meter != nil {
+			p.meter.startFrame(int64(size) + int64(len(p.header)))
+		}
+	}
+	return nil
+}
+
+func (p *framedTransport) Read(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	if err := p.readHeader(); err != nil {
+		return 0, err
+	}
+	if uint32(len(buf)) > p.remaining {
+		buf = buf[:p.remaining]
+	}
+	n, err := p.reader.Read(buf)
+	p.remaining -= uint32(n)
+	return n, thrift.NewTTransportExceptionFromError(err)
+}
+
+func (p *framedTransport) ReadByte() (byte, error) {
+	if err := p.readHeader(); err != nil {
+		return 0, err
+	}
+	c, err := p.reader.ReadByte()
+	if err != nil {
+		return 0, thrift.NewTTransportExceptionFromError(err)
+	}
+	p.remaining--
+	return c, nil
+}
+
+func (p *framedTransport) RemainingBytes() uint64 {
+	return uint64(p.remaining)
+}
+
+// discardFrame 丢弃当前帧剩余的内容，下一次读取从新的帧开始
+func (p *framedTransport) discardFrame() error {
+	n, err := p.reader.Discard(int(p.remaining))
+	p.remaining -= uint32(n)
+	return thrift.NewTTransportExceptionFromError(err)
+}
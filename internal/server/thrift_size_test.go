@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"encoding/binary"
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// dialTransportClient 使用指定传输层创建 binary 协议的 UserService 客户端
+func dialTransportClient(t *testing.T, addr, transport string) *user_service.UserServiceClient {
+	t.Helper()
+	cfg := &thrift.TConfiguration{ConnectTimeout: time.Second, SocketTimeout: 5 * time.Second}
+	transportFactory, protocolFactory, err := thriftx.NewFactories("", transport, cfg)
+	if err != nil {
+		t.Fatalf("创建工厂失败: %v", err)
+	}
+	trans, err := transportFactory.GetTransport(thrift.NewTSocketConf(addr, cfg))
+	if err != nil {
+		t.Fatalf("创建传输层失败: %v", err)
+	}
+	if err := trans.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	t.Cleanup(func() {
+		trans.Close()
+	})
+	protocol := thrift.NewTMultiplexedProtocol(protocolFactory.GetProtocol(trans), "UserService")
+	return user_service.NewUserServiceClient(thrift.NewTStandardClient(protocol, protocol))
+}
+
+// isRequestTooLarge 判断是否为 REQUEST_TOO_LARGE 应用异常
+func isRequestTooLarge(err error) bool {
+	var appErr thrift.TApplicationException
+	return stderrors.As(err, &appErr) && appErr.TypeId() == thriftx.REQUEST_TOO_LARGE
+}
+
+// TestThriftMethodMaxRequestSize 测试超出方法上限的请求返回 REQUEST_TOO_LARGE，且连接仍然可用
+func TestThriftMethodMaxRequestSize(t *testing.T) {
+	for _, transport := range []string{thriftx.TransportBuffered, thriftx.TransportFramed, thriftx.TransportHeader} {
+		t.Run(transport, func(t *testing.T) {
+			addr := startTestServer(t, &conf.Server_Thrift{
+				Transport:             transport,
+				MethodMaxRequestSizes: map[string]int32{"UserService.echoData": 1024},
+			})
+			client := dialTransportClient(t, addr, transport)
+			if err := callEcho(client, "hello"); err != nil {
+				t.Fatalf("调用 EchoData 失败: %v", err)
+			}
+
+			// 请求在一次读取内到达，服务端跳过请求体后返回异常，连接保持可用
+			err := callEcho(client, strings.Repeat("x", 1500))
+			if !isRequestTooLarge(err) {
+				t.Fatalf("期望返回 REQUEST_TOO_LARGE, 实际: %v", err)
+			}
+			if err := callEcho(client, "hello"); err != nil {
+				t.Fatalf("超限后连接不可用: %v", err)
+			}
+
+			// 请求体远大于上限时 buffered 传输层读取中途失败，服务端返回异常后关闭连接，不影响新连接
+			if err := callEcho(client, strings.Repeat("x", 64*1024)); err == nil {
+				t.Fatal("期望超大请求失败")
+			}
+			if err := callEcho(dialTransportClient(t, addr, transport), "hello"); err != nil {
+				t.Fatalf("新连接调用失败: %v", err)
+			}
+		})
+	}
+}
+
+// TestThriftFramedMaxRequestSize 测试 framed 传输层按帧头的长度拒绝超限的请求：帧内容还没有到达时就返回
+// REQUEST_TOO_LARGE，之后剩余的帧内容被丢弃，连接仍然可用
+func TestThriftFramedMaxRequestSize(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{
+		Transport:             thriftx.TransportFramed,
+		MethodMaxRequestSizes: map[string]int32{"UserService.echoData": 1024},
+	})
+	ctx := context.Background()
+	cfg := &thrift.TConfiguration{ConnectTimeout: time.Second, SocketTimeout: 5 * time.Second}
+	socket := thrift.NewTSocketConf(addr, cfg)
+	if err := socket.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	defer socket.Close()
+
+	// 编码一个 64KB 的请求帧，先只发送帧头与消息头
+	buf := thrift.NewTMemoryBuffer()
+	req := thrift.NewTBinaryProtocolConf(buf, nil)
+	req.WriteMessageBegin(ctx, "UserService:echoData", thrift.CALL, 1)
+	args := user_service.NewUserServiceEchoDataArgs()
+	args.ClientData = []byte(strings.Repeat("x", 64*1024))
+	args.User = &user_service.User{ID: 1}
+	args.Write(ctx, req)
+	req.WriteMessageEnd(ctx)
+	body := buf.Bytes()
+	frame := binary.BigEndian.AppendUint32(nil, uint32(len(body)))
+	frame = append(frame, body[:64]...)
+	if _, err := socket.Write(frame); err != nil {
+		t.Fatalf("发送帧头失败: %v", err)
+	}
+
+	trans := thrift.NewTFramedTransportConf(socket, cfg)
+	reply := thrift.NewTBinaryProtocolConf(trans, nil)
+	_, typeID, _, err := reply.ReadMessageBegin(ctx)
+	if err != nil || typeID != thrift.EXCEPTION {
+		t.Fatalf("期望帧内容到达前返回异常, 实际: %v %v", typeID, err)
+	}
+	exc := thrift.NewTApplicationException(thrift.UNKNOWN_APPLICATION_EXCEPTION, "")
+	if err := exc.Read(ctx, reply); err != nil || !isRequestTooLarge(exc) {
+		t.Fatalf("期望返回 REQUEST_TOO_LARGE, 实际: %v %v", exc, err)
+	}
+	reply.ReadMessageEnd(ctx)
+
+	// 发送剩余的帧内容后连接继续可用
+	if _, err := socket.Write(body[64:]); err != nil {
+		t.Fatalf("发送帧内容失败: %v", err)
+	}
+	protocol := thrift.NewTMultiplexedProtocol(reply, "UserService")
+	client := user_service.NewUserServiceClient(thrift.NewTStandardClient(protocol, protocol))
+	if err := callEcho(client, "hello"); err != nil {
+		t.Fatalf("超限后连接不可用: %v", err)
+	}
+}
+
+// TestThriftMaxMessageSize 测试全局 max_message_size 限制单条消息的大小
+func TestThriftMaxMessageSize(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{MaxMessageSize: 4096})
+	client := dialTransportClient(t, addr, thriftx.TransportBuffered)
+	if err := callEcho(client, strings.Repeat("x", 1024)); err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	if err := callEcho(dialTransportClient(t, addr, thriftx.TransportBuffered), strings.Repeat("x", 8192)); err == nil {
+		t.Fatal("期望超出 max_message_size 的请求失败")
+	}
+}
+
+// TestThriftHTTPMethodMaxRequestSize 测试 thrift over http 同样按方法限制请求大小
+func TestThriftHTTPMethodMaxRequestSize(t *testing.T) {
+	baseURL := startTestHTTPServer(t, &conf.Server{
+		Thrift: &conf.Server_Thrift{MethodMaxRequestSizes: map[string]int32{"UserService.echoData": 1024}},
+		Http:   &conf.Server_HTTP{},
+	})
+	trans, err := thrift.NewTHttpClient(baseURL + defaultThriftPath)
+	if err != nil {
+		t.Fatalf("创建 HTTP 客户端失败: %v", err)
+	}
+	protocol := thrift.NewTMultiplexedProtocol(thrift.NewTBinaryProtocolConf(trans, nil), "UserService")
+	client := user_service.NewUserServiceClient(thrift.NewTStandardClient(protocol, protocol))
+	echo(t, client)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = client.EchoData(ctx, []byte(strings.Repeat("x", 64*1024)), &user_service.User{ID: 1})
+	if !isRequestTooLarge(err) {
+		t.Fatalf("期望返回 REQUEST_TOO_LARGE, 实际: %v", err)
+	}
+	echo(t, client)
+}
@@ -0,0 +1,441 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2"
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/middleware"
+	"github.com/go-kratos/kratos/v2/middleware/recovery"
+	"github.com/go-kratos/kratos/v2/middleware/validate"
+	"github.com/go-kratos/kratos/v2/registry"
+)
+
+// startTestServer 按配置启动 thrift 服务端，返回监听地址
+func startTestServer(t *testing.T, c *conf.Server_Thrift, opts ...ThriftServerOption) string {
+	t.Helper()
+	_, addr := startTestServerWith(t, c, &thrifttest.UserService{}, opts...)
+	return addr
+}
+
+// startTestServerWith 使用指定的用户服务启动 thrift 服务端，返回服务端与监听地址
+func startTestServerWith(t *testing.T, c *conf.Server_Thrift, user user_service.UserService, opts ...ThriftServerOption) (*ThriftServer, string) {
+	t.Helper()
+	if c.Addr == "" {
+		c.Addr = thrifttest.FreeAddr(t)
+	}
+	srv, err := NewThriftServer(&conf.Server{Thrift: c}, user, &thrifttest.GiftService{}, opts...)
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	thrifttest.Start(t, srv, c.Network, c.Addr)
+	return srv, c.Addr
+}
+
+// newTestUserClient 使用指定协议创建 UserService 客户端
+func newTestUserClient(t *testing.T, addr string, newProtocol func(thrift.TTransport) thrift.TProtocol) *user_service.UserServiceClient {
+	t.Helper()
+	socket := thrift.NewTSocketConf(addr, &thrift.TConfiguration{ConnectTimeout: time.Second, SocketTimeout: 5 * time.Second})
+	transport := thrift.NewTBufferedTransport(socket, 2048)
+	if err := transport.Open(); err != nil {
+		t.Fatalf("打开连接失败: %v", err)
+	}
+	t.Cleanup(func() {
+		transport.Close()
+	})
+	protocol := thrift.NewTMultiplexedProtocol(newProtocol(transport), "UserService")
+	return user_service.NewUserServiceClient(thrift.NewTStandardClient(protocol, protocol))
+}
+
+func echo(t *testing.T, client *user_service.UserServiceClient) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := client.EchoData(ctx, []byte("hello"), &user_service.User{ID: 1, Name: "test"})
+	if err != nil {
+		t.Fatalf("调用 EchoData 失败: %v", err)
+	}
+	if string(resp.ClientData) != "hello" {
+		t.Fatalf("EchoData 返回数据不一致: %q", resp.ClientData)
+	}
+}
+
+func binaryProtocol(trans thrift.TTransport) thrift.TProtocol {
+	return thrift.NewTBinaryProtocolConf(trans, nil)
+}
+
+func compactProtocol(trans thrift.TTransport) thrift.TProtocol {
+	return thrift.NewTCompactProtocolConf(trans, nil)
+}
+
+func jsonProtocol(trans thrift.TTransport) thrift.TProtocol {
+	return thrift.NewTJSONProtocol(trans)
+}
+
+func headerProtocol(trans thrift.TTransport) thrift.TProtocol {
+	return thrift.NewTHeaderProtocolConf(trans, nil)
+}
+
+// TestThriftServerProtocol 测试服务端按配置使用不同协议
+func TestThriftServerProtocol(t *testing.T) {
+	cases := []struct {
+		name     string
+		protocol string
+		clients  map[string]func(thrift.TTransport) thrift.TProtocol
+	}{
+		{"默认", "", map[string]func(thrift.TTransport) thrift.TProtocol{"binary": binaryProtocol}},
+		{"binary", "binary", map[string]func(thrift.TTransport) thrift.TProtocol{"binary": binaryProtocol}},
+		{"compact", "compact", map[string]func(thrift.TTransport) thrift.TProtocol{"compact": compactProtocol}},
+		{"json", "json", map[string]func(thrift.TTransport) thrift.TProtocol{"json": jsonProtocol}},
+		{"header", "header", map[string]func(thrift.TTransport) thrift.TProtocol{"header": headerProtocol}},
+		{"auto", "auto", map[string]func(thrift.TTransport) thrift.TProtocol{
+			"binary":  binaryProtocol,
+			"compact": compactProtocol,
+			"header":  headerProtocol,
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			addr := startTestServer(t, &conf.Server_Thrift{Protocol: c.protocol})
+			for name, newProtocol := range c.clients {
+				t.Run(name, func(t *testing.T) {
+					echo(t, newTestUserClient(t, addr, newProtocol))
+				})
+			}
+		})
+	}
+}
+
+// TestThriftServerUnknownProtocol 测试配置了不支持的协议时返回错误
+func TestThriftServerUnknownProtocol(t *testing.T) {
+	_, err := NewThriftServer(&conf.Server{Thrift: &conf.Server_Thrift{Addr: thrifttest.FreeAddr(t), Protocol: "xml"}}, &thrifttest.UserService{}, &thrifttest.GiftService{})
+	if err == nil {
+		t.Fatal("期望不支持的协议返回错误")
+	}
+}
+
+// TestThriftMiddleware 测试 kratos 中间件包裹 thrift 调用
+func TestThriftMiddleware(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		calls  []string
+		reject = true
+	)
+	record := func(call string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+		return reject
+	}
+	addr := startTestServer(t, &conf.Server_Thrift{}, ThriftMiddleware(
+		func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				if record("before") {
+					return nil, errors.ServiceUnavailable("RATELIMIT", "rejected")
+				}
+				reply, err := handler(ctx, req)
+				record("after")
+				return reply, err
+			}
+		},
+	))
+	client := newTestUserClient(t, addr, binaryProtocol)
+
+	// 中间件直接拒绝时客户端收到 LOADSHEDDING，可以重试
+	_, err := client.EchoData(context.Background(), []byte("hello"), &user_service.User{})
+	var exc thrift.TApplicationException
+	if !stderrors.As(err, &exc) || exc.TypeId() != thriftx.LOADSHEDDING {
+		t.Fatalf("期望收到 LOADSHEDDING 异常, 实际: %v", err)
+	}
+
+	// 同一连接继续可用
+	mu.Lock()
+	reject = false
+	mu.Unlock()
+	echo(t, client)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(calls) != 3 || calls[2] != "after" {
+		t.Fatalf("中间件调用顺序不符合预期: %v", calls)
+	}
+}
+
+// TestThriftMiddlewareErrors 测试中间件返回的 kratos 错误按错误码转换为对应类型的异常
+func TestThriftMiddlewareErrors(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int32
+	}{
+		{errors.New(http.StatusTooManyRequests, "RATELIMIT", "rate limit exceeded"), thriftx.LOADSHEDDING},
+		{errors.ServiceUnavailable("CIRCUITBREAKER", "request failed due to circuit breaker triggered"), thriftx.LOADSHEDDING},
+		{errors.GatewayTimeout("TIMEOUT", "deadline exceeded"), thriftx.TIMEOUT},
+		{context.DeadlineExceeded, thriftx.TIMEOUT},
+		{errors.Unauthorized("UNAUTHORIZED", "token is missing"), thriftx.UNAUTHORIZED},
+		{errors.Forbidden("FORBIDDEN", "forbidden"), thriftx.FORBIDDEN},
+		{errors.NotFound("USER_NOT_FOUND", "user not found"), thriftx.NOT_FOUND},
+		{errors.Conflict("CONFLICT", "conflict"), thrift.PROTOCOL_ERROR},
+		{errors.InternalServer("INTERNAL", "internal"), thrift.INTERNAL_ERROR},
+		{stderrors.New("plain error"), thrift.INTERNAL_ERROR},
+	}
+	var next atomic.Pointer[error]
+	addr := startTestServer(t, &conf.Server_Thrift{}, ThriftMiddleware(
+		func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				return nil, *next.Load()
+			}
+		},
+	))
+	client := newTestUserClient(t, addr, binaryProtocol)
+
+	for _, c := range cases {
+		next.Store(&c.err)
+		_, err := client.EchoData(context.Background(), []byte("hello"), &user_service.User{})
+		var exc thrift.TApplicationException
+		if !stderrors.As(err, &exc) || exc.TypeId() != c.want {
+			t.Errorf("%v: 期望收到类型为 %d 的异常, 实际: %v", c.err, c.want, err)
+		}
+	}
+}
+
+// errUserService EchoData 执行后返回 err
+type errUserService struct {
+	thrifttest.UserService
+	err atomic.Pointer[error]
+}
+
+func (s *errUserService) EchoData(ctx context.Context, clientData []byte, user *user_service.User) (*user_service.EchoResponse, error) {
+	return nil, *s.err.Load()
+}
+
+// TestThriftHandlerErrors 测试处理函数执行之后的 429、503 为 INTERNAL_ERROR，处理函数执行之前的为 LOADSHEDDING
+func TestThriftHandlerErrors(t *testing.T) {
+	user := &errUserService{}
+	var before, after atomic.Pointer[error]
+	_, addr := startTestServerWith(t, &conf.Server_Thrift{}, user, ThriftMiddleware(
+		func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				if err := before.Load(); err != nil {
+					return nil, *err
+				}
+				reply, err := handler(ctx, req)
+				if err := after.Load(); err != nil {
+					return nil, *err
+				}
+				return reply, err
+			}
+		},
+	))
+
+	unavailable := error(errors.ServiceUnavailable("REDIS", "connection refused"))
+	cases := []struct {
+		name          string
+		before, after error
+		handler       error
+		want          int32
+	}{
+		{"处理函数返回 503", nil, nil, unavailable, thrift.INTERNAL_ERROR},
+		{"处理函数返回 429", nil, nil, errors.New(http.StatusTooManyRequests, "QUOTA", "quota exceeded"), thrift.INTERNAL_ERROR},
+		{"处理函数返回传输层错误", nil, nil, thrift.NewTTransportException(thrift.NOT_OPEN, "redis: connection closed"), thrift.INTERNAL_ERROR},
+		{"处理函数返回 LOADSHEDDING", nil, nil, thriftx.NewLoadSheddingException("overloaded"), thrift.INTERNAL_ERROR},
+		{"处理函数返回 404", nil, nil, errors.NotFound("USER_NOT_FOUND", "user not found"), thriftx.NOT_FOUND},
+		{"处理函数执行之后中间件返回 503", nil, unavailable, unavailable, thrift.INTERNAL_ERROR},
+		{"处理函数执行之前中间件返回 503", unavailable, nil, unavailable, thriftx.LOADSHEDDING},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			user.err.Store(&c.handler)
+			before.Store(nil)
+			if c.before != nil {
+				before.Store(&c.before)
+			}
+			after.Store(nil)
+			if c.after != nil {
+				after.Store(&c.after)
+			}
+			// 处理函数返回传输层错误时服务端写回异常后关闭连接，每个用例使用新的连接
+			client := newTestUserClient(t, addr, binaryProtocol)
+			_, err := client.EchoData(context.Background(), []byte("hello"), &user_service.User{})
+			var exc thrift.TApplicationException
+			if !stderrors.As(err, &exc) || exc.TypeId() != c.want {
+				t.Fatalf("期望收到类型为 %d 的异常, 实际: %v", c.want, err)
+			}
+		})
+	}
+}
+
+// TestThriftMiddlewareRecovery 测试 recovery 中间件拦截处理函数 panic
+func TestThriftMiddlewareRecovery(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{}, ThriftMiddleware(recovery.Recovery()))
+	client := newTestUserClient(t, addr, binaryProtocol)
+
+	_, err := client.EchoData(context.Background(), []byte("panic"), &user_service.User{})
+	var exc thrift.TApplicationException
+	if !stderrors.As(err, &exc) || exc.TypeId() != thrift.INTERNAL_ERROR {
+		t.Fatalf("期望收到 INTERNAL_ERROR 异常, 实际: %v", err)
+	}
+	echo(t, client)
+}
+
+// TestThriftMiddlewareRequest 测试中间件拿到解码后的参数与结果结构体，响应在中间件返回之后才写回
+func TestThriftMiddlewareRequest(t *testing.T) {
+	addr := startTestServer(t, &conf.Server_Thrift{}, ThriftMiddleware(
+		func(handler middleware.Handler) middleware.Handler {
+			return func(ctx context.Context, req any) (any, error) {
+				args, ok := req.(*user_service.UserServiceEchoDataArgs)
+				if !ok || string(args.ClientData) != "hello" || args.User.GetName() != "test" {
+					return nil, errors.BadRequest("ARGS", "unexpected request")
+				}
+				reply, err := handler(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				reply.(*user_service.UserServiceEchoDataResult).Success.ServerId = 42
+				return reply, nil
+			}
+		},
+	))
+	client := newTestUserClient(t, addr, binaryProtocol)
+
+	resp, err := client.EchoData(context.Background(), []byte("hello"), &user_service.User{ID: 1, Name: "test"})
+	if err != nil {
+		t.Fatalf("调用失败: %v", err)
+	}
+	if resp.ServerId != 42 || string(resp.ClientData) != "hello" {
+		t.Fatalf("期望返回中间件修改后的结果, 实际: %+v", resp)
+	}
+}
+
+// validatedEchoArgs clientData 为空时校验失败的 echoData 参数
+type validatedEchoArgs struct {
+	*user_service.UserServiceEchoDataArgs
+}
+
+func (a validatedEchoArgs) Validate() error {
+	if len(a.ClientData) == 0 {
+		return stderrors.New("clientData is required")
+	}
+	return nil
+}
+
+// TestThriftMiddlewareValidate 测试 validate 中间件的校验失败返回给客户端，连接继续可用
+func TestThriftMiddlewareValidate(t *testing.T) {
+	orig := thriftMethods["UserService:echoData"]
+	t.Cleanup(func() { thriftMethods["UserService:echoData"] = orig })
+	thriftMethods["UserService:echoData"] = thriftMethod{
+		args:   func() thrift.TStruct { return validatedEchoArgs{user_service.NewUserServiceEchoDataArgs()} },
+		result: orig.result,
+	}
+
+	addr := startTestServer(t, &conf.Server_Thrift{}, ThriftMiddleware(validate.Validator()))
+	client := newTestUserClient(t, addr, binaryProtocol)
+
+	_, err := client.EchoData(context.Background(), nil, &user_service.User{})
+	var exc thrift.TApplicationException
+	if !stderrors.As(err, &exc) || exc.TypeId() != thrift.PROTOCOL_ERROR ||
+		!strings.Contains(exc.Error(), "VALIDATOR") || !strings.Contains(exc.Error(), "clientData is required") {
+		t.Fatalf("期望收到校验失败的异常, 实际: %v", err)
+	}
+	echo(t, client)
+}
+
+// TestThriftServerEndpoint 测试 Endpoint 返回实际监听的地址
+func TestThriftServerEndpoint(t *testing.T) {
+	srv, err := NewThriftServer(&conf.Server{Thrift: &conf.Server_Thrift{Addr: "127.0.0.1:0"}}, &thrifttest.UserService{}, &thrifttest.GiftService{})
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	defer srv.Stop(context.Background())
+
+	u, err := srv.Endpoint()
+	if err != nil {
+		t.Fatalf("获取 endpoint 失败: %v", err)
+	}
+	if u.Scheme != "thrift" || u.Hostname() != "127.0.0.1" || u.Port() == "0" || u.Port() == "" {
+		t.Fatalf("endpoint 不符合预期: %s", u)
+	}
+	// Endpoint 已经监听，直接可以连接
+	conn, err := net.Dial("tcp", u.Host)
+	if err != nil {
+		t.Fatalf("连接 endpoint 失败: %v", err)
+	}
+	conn.Close()
+}
+
+// TestThriftServerRegistry 测试 kratos 应用启动后注册 thrift 地址，停止后注销
+func TestThriftServerRegistry(t *testing.T) {
+	srv, err := NewThriftServer(&conf.Server{Thrift: &conf.Server_Thrift{Addr: "127.0.0.1:0"}}, &thrifttest.UserService{}, &thrifttest.GiftService{})
+	if err != nil {
+		t.Fatalf("创建服务端失败: %v", err)
+	}
+	rr, err := NewRegistrar(&conf.Registry{Type: "file", Path: filepath.Join(t.TempDir(), "registry.json")})
+	if err != nil {
+		t.Fatalf("创建注册中心失败: %v", err)
+	}
+	discovery := rr.(registry.Discovery)
+	app := kratos.New(kratos.Name("above-thrift"), kratos.Server(srv), kratos.Registrar(rr))
+	errc := make(chan error, 1)
+	go func() {
+		errc <- app.Run()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	w, err := discovery.Watch(ctx, "above-thrift")
+	if err != nil {
+		t.Fatalf("创建 watcher 失败: %v", err)
+	}
+	defer w.Stop()
+	services, err := w.Next()
+	if err != nil {
+		t.Fatalf("等待注册失败: %v", err)
+	}
+	if len(services) != 1 || len(services[0].Endpoints) != 1 {
+		t.Fatalf("注册的实例不符合预期: %v", services)
+	}
+	u, err := url.Parse(services[0].Endpoints[0])
+	if err != nil || u.Scheme != "thrift" {
+		t.Fatalf("注册的 endpoint 不符合预期: %v", services[0].Endpoints)
+	}
+	// 通过发现的地址调用服务
+	echo(t, newTestUserClient(t, u.Host, binaryProtocol))
+
+	if err := app.Stop(); err != nil {
+		t.Fatalf("停止应用失败: %v", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("应用运行失败: %v", err)
+	}
+	services, err = discovery.GetService(ctx, "above-thrift")
+	if err != nil || len(services) != 0 {
+		t.Fatalf("期望停止后注销, 实际: %v, %v", services, err)
+	}
+}
+
+// TestNewRegistrar 测试未配置注册中心时不注册，配置错误时返回错误
+func TestNewRegistrar(t *testing.T) {
+	if rr, err := NewRegistrar(nil); rr != nil || err != nil {
+		t.Fatalf("期望未配置时返回 nil, 实际: %v, %v", rr, err)
+	}
+	if _, err := NewRegistrar(&conf.Registry{Type: "file"}); err == nil {
+		t.Fatal("期望未配置 path 时返回错误")
+	}
+	if _, err := NewRegistrar(&conf.Registry{Type: "etcd"}); err == nil {
+		t.Fatal("期望不支持的类型返回错误")
+	}
+}
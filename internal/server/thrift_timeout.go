@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"aboveThriftRPC/internal/pkg/metrics"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+)
+
+// timeoutProcessor 为每次调用设置截止时间，键为 Service.method 的 overrides 优先于默认的 timeout
+//
+// 处理函数的 ctx 在截止时间被取消；如果此时还没有开始写回响应，客户端收到 TIMEOUT 异常，
+// 处理函数之后写回的结果与设置的响应头被丢弃，连接可以继续处理下一个请求。
+//
+// 超时后处理函数所在的协程不会被强制结束，service、biz、data 各层需要把 ctx 传下去才能及时返回；
+// 没有响应取消的处理函数计入 thrift_server_abandoned_handlers，返回前仍占用 max_in_flight 的名额。
+func timeoutProcessor(timeout time.Duration, overrides map[string]time.Duration) thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		d := timeout
+		if override, ok := overrides[strings.Replace(name, thrift.MULTIPLEXED_SEPARATOR, ".", 1)]; ok {
+			d = override
+		}
+		if d <= 0 {
+			return next
+		}
+		service, method, ok := strings.Cut(name, thrift.MULTIPLEXED_SEPARATOR)
+		if !ok {
+			service, method = "", name
+		}
+		abandoned := metrics.ServerAbandonedHandlers.WithLabelValues(service, method)
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				callCtx, cancel := context.WithTimeout(ctx, d)
+				defer cancel()
+				// 超时的处理函数可能还在设置响应头，不能直接写到连接上与下一个请求竞争，
+				// 先写到自己的缓冲，开始写回响应时再复制到连接
+				helper, _ := thrift.GetResponseHelper(ctx)
+				helper.ClearHeaders()
+				header := &bufferedReplyHeader{}
+				callCtx = context.WithValue(callCtx, bufferedReplyHeaderKey{}, header)
+
+				args := &notifyArgsProtocol{TProtocol: in, done: make(chan struct{})}
+				reply := &timeoutProtocol{TProtocol: out, ctx: callCtx, header: header, helper: helper}
+				type result struct {
+					ok  bool
+					exc thrift.TException
+				}
+				results := make(chan result, 1)
+				go func() {
+					ok, exc := next.Process(callCtx, seqID, args, reply)
+					results <- result{ok, exc}
+				}()
+
+				select {
+				case r := <-results:
+					if !reply.isExpired() {
+						return r.ok, r.exc
+					}
+				case <-callCtx.Done():
+					// 参数还没读完时不能并发读取连接，等处理函数读完参数
+					select {
+					case r := <-results:
+						if !reply.expire() {
+							return r.ok, r.exc
+						}
+					case <-args.done:
+						if !reply.expire() {
+							// 响应已经开始写入，等待写完
+							r := <-results
+							return r.ok, r.exc
+						}
+						// 处理函数没有响应 ctx 的取消，在后台继续运行直到返回
+						abandoned.Inc()
+						go func() {
+							<-results
+							abandoned.Dec()
+						}()
+					}
+				}
+				exc := thriftx.NewTimeoutException("timeout processing " + methodName(name) + " after " + d.String())
+				return writeException(ctx, methodName(name), seqID, &argsProtocol{TProtocol: in, read: true}, out, exc)
+			},
+		}
+	}
+}
+
+// notifyArgsProtocol 请求参数读取完毕时关闭 done
+type notifyArgsProtocol struct {
+	thrift.TProtocol
+	once sync.Once
+	done chan struct{}
+}
+
+func (p *notifyArgsProtocol) ReadMessageEnd(ctx context.Context) error {
+	err := p.TProtocol.ReadMessageEnd(ctx)
+	p.once.Do(func() { close(p.done) })
+	return err
+}
+
+type bufferedReplyHeaderKey struct{}
+
+// bufferedReplyHeader 处理函数自己的响应头，只在截止时间之前开始写回响应时复制到连接
+type bufferedReplyHeader struct {
+	mu     sync.Mutex
+	header map[string]string
+}
+
+func (h *bufferedReplyHeader) SetHeader(key, value string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.header == nil {
+		h.header = make(map[string]string)
+	}
+	h.header[key] = value
+}
+
+func (h *bufferedReplyHeader) ClearHeaders() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header = nil
+}
+
+func (h *bufferedReplyHeader) copyTo(helper thrift.TResponseHelper) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key, value := range h.header {
+		helper.SetHeader(key, value)
+	}
+}
+
+// timeoutProtocol 超时后丢弃处理函数写回的响应
+//
+// 超时后内嵌的协议被替换为写入内存的协议。处理函数的写入总是从 WriteMessageBegin 开始，
+// 只要在这里加锁，后续的写入就能看到替换后的协议。ctx 已经超时才开始写入的响应同样丢弃，
+// 避免处理函数因 ctx 取消返回的错误抢在 TIMEOUT 异常之前写回。
+// 响应在截止时间之前开始写入时，header 中的响应头在这里复制到连接的 helper。
+type timeoutProtocol struct {
+	thrift.TProtocol
+	ctx     context.Context
+	header  *bufferedReplyHeader
+	helper  thrift.TResponseHelper
+	mu      sync.Mutex
+	started bool
+	expired bool
+}
+
+// expire 在响应还没开始写入时标记为超时，之后的写入全部丢弃
+func (p *timeoutProtocol) expire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.expired && !p.started {
+		p.discardLocked()
+	}
+	return p.expired
+}
+
+func (p *timeoutProtocol) isExpired() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.expired
+}
+
+func (p *timeoutProtocol) discardLocked() {
+	p.expired = true
+	p.TProtocol = thrift.NewTBinaryProtocolConf(thrift.NewTMemoryBuffer(), nil)
+}
+
+func (p *timeoutProtocol) WriteMessageBegin(ctx context.Context, name string, typeID thrift.TMessageType, seqID int32) error {
+	p.mu.Lock()
+	if !p.expired && !p.started {
+		if p.ctx.Err() != nil {
+			p.discardLocked()
+		} else {
+			p.started = true
+			p.header.copyTo(p.helper)
+		}
+	}
+	w := p.TProtocol
+	p.mu.Unlock()
+	return w.WriteMessageBegin(ctx, name, typeID, seqID)
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	"aboveThriftRPC/internal/conf"
+	"aboveThriftRPC/internal/pkg/metrics"
+	"aboveThriftRPC/internal/pkg/thrifttest"
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/transport"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// slowUserService clientData 为 slow 时等待 delay 或 ctx 取消，并记录 ctx 的错误
+type slowUserService struct {
+	thrifttest.UserService
+	delay     time.Duration
+	cancelled chan error
+}
+
+func (s *slowUserService) EchoData(ctx context.Context, clientData []byte, user *user_service.User) (*user_service.EchoResponse, error) {
+	if string(clientData) == "slow" {
+		select {
+		case <-ctx.Done():
+			s.cancelled <- ctx.Err()
+			return nil, ctx.Err()
+		case <-time.After(s.delay):
+		}
+	}
+	return s.UserService.EchoData(ctx, clientData, user)
+}
+
+func isTimeoutException(err error) bool {
+	var exc thrift.TApplicationException
+	return stderrors.As(err, &exc) && exc.TypeId() == thriftx.TIMEOUT
+}
+
+// TestThriftTimeout 测试超过 timeout 时取消处理函数的 ctx 并返回 TIMEOUT 异常
+func TestThriftTimeout(t *testing.T) {
+	user := &slowUserService{delay: 5 * time.Second, cancelled: make(chan error, 1)}
+	_, addr := startTestServerWith(t, &conf.Server_Thrift{Timeout: durationpb.New(100 * time.Millisecond)}, user)
+	client, _ := dialUserClient(t, addr)
+
+	start := time.Now()
+	if err := callEcho(client, "slow"); !isTimeoutException(err) {
+		t.Fatalf("期望收到 TIMEOUT 异常, 实际: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("超时异常返回过慢: %v", elapsed)
+	}
+	select {
+	case err := <-user.cancelled:
+		if !stderrors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("处理函数 ctx 错误不符合预期: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("处理函数的 ctx 未被取消")
+	}
+	if err := callEcho(client, "hello"); err != nil {
+		t.Fatalf("超时后连接不可用: %v", err)
+	}
+}
+
+// TestThriftTimeoutHandlerIgnoresContext 测试处理函数忽略 ctx 时客户端仍按时收到异常，迟到的结果被丢弃
+func TestThriftTimeoutHandlerIgnoresContext(t *testing.T) {
+	user := newBlockingUserService()
+	_, addr := startTestServerWith(t, &conf.Server_Thrift{Timeout: durationpb.New(100 * time.Millisecond)}, user)
+	client, _ := dialUserClient(t, addr)
+
+	abandoned := metrics.ServerAbandonedHandlers.WithLabelValues("UserService", "echoData")
+	if err := callEcho(client, "block"); !isTimeoutException(err) {
+		t.Fatalf("期望收到 TIMEOUT 异常, 实际: %v", err)
+	}
+	if got := testutil.ToFloat64(abandoned); got != 1 {
+		t.Fatalf("期望 1 个仍在运行的超时处理函数, 实际 %v", got)
+	}
+	close(user.release)
+	// 迟到的响应不会被下一次调用读到
+	for i := 0; i < 3; i++ {
+		if err := callEcho(client, "hello"); err != nil {
+			t.Fatalf("超时后连接不可用: %v", err)
+		}
+	}
+	// 处理函数返回后不再计数
+	for i := 0; i < 50 && testutil.ToFloat64(abandoned) != 0; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := testutil.ToFloat64(abandoned); got != 0 {
+		t.Fatalf("处理函数返回后仍有 %v 个超时处理函数", got)
+	}
+}
+
+// TestThriftMethodTimeout 测试按方法覆盖默认的 timeout
+func TestThriftMethodTimeout(t *testing.T) {
+	user := &slowUserService{delay: 200 * time.Millisecond, cancelled: make(chan error, 1)}
+	cases := []struct {
+		name      string
+		overrides map[string]*durationpb.Duration
+		timeout   bool
+	}{
+		{"默认", nil, true},
+		{"覆盖", map[string]*durationpb.Duration{"UserService.echoData": durationpb.New(5 * time.Second)}, false},
+		{"其他方法", map[string]*durationpb.Duration{"GiftService.GetTop10Senders": durationpb.New(5 * time.Second)}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, addr := startTestServerWith(t, &conf.Server_Thrift{
+				Timeout:        durationpb.New(50 * time.Millisecond),
+				MethodTimeouts: c.overrides,
+			}, user)
+			client, _ := dialUserClient(t, addr)
+			err := callEcho(client, "slow")
+			if c.timeout && !isTimeoutException(err) {
+				t.Fatalf("期望收到 TIMEOUT 异常, 实际: %v", err)
+			}
+			if !c.timeout && err != nil {
+				t.Fatalf("期望调用成功, 实际: %v", err)
+			}
+			select {
+			case <-user.cancelled:
+			default:
+			}
+		})
+	}
+}
+
+// lateHeaderUserService clientData 为 late 时忽略 ctx 的取消，在下一个请求处理过程中设置响应头
+type lateHeaderUserService struct {
+	thrifttest.UserService
+	next chan struct{}
+	set  chan struct{}
+}
+
+func (s *lateHeaderUserService) EchoData(ctx context.Context, clientData []byte, user *user_service.User) (*user_service.EchoResponse, error) {
+	tr, _ := transport.FromServerContext(ctx)
+	switch string(clientData) {
+	case "late":
+		<-ctx.Done()
+		<-s.next
+		tr.ReplyHeader().Set("x-late", "late")
+		close(s.set)
+		return nil, ctx.Err()
+	case "hello":
+		tr.ReplyHeader().Set("x-echo", "hello")
+		close(s.next)
+		<-s.set
+	}
+	return s.UserService.EchoData(ctx, clientData, user)
+}
+
+// TestThriftTimeoutReplyHeader 测试超时的处理函数设置的响应头不会出现在连接上下一个请求的响应中
+func TestThriftTimeoutReplyHeader(t *testing.T) {
+	user := &lateHeaderUserService{next: make(chan struct{}), set: make(chan struct{})}
+	_, addr := startTestServerWith(t, &conf.Server_Thrift{Protocol: "header", Timeout: durationpb.New(100 * time.Millisecond)}, user)
+	var header *thrift.THeaderProtocol
+	client := newTestUserClient(t, addr, func(trans thrift.TTransport) thrift.TProtocol {
+		header = thrift.NewTHeaderProtocolConf(trans, nil)
+		return header
+	})
+
+	if err := callEcho(client, "late"); !isTimeoutException(err) {
+		t.Fatalf("期望收到 TIMEOUT 异常, 实际: %v", err)
+	}
+	if err := callEcho(client, "hello"); err != nil {
+		t.Fatalf("超时后连接不可用: %v", err)
+	}
+	headers := header.GetReadHeaders()
+	if headers["x-echo"] != "hello" {
+		t.Fatalf("期望按时完成的处理函数设置的响应头被写回, 实际: %v", headers)
+	}
+	if _, ok := headers["x-late"]; ok {
+		t.Fatalf("超时的处理函数设置的响应头出现在下一个请求的响应中: %v", headers)
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"aboveThriftRPC/internal/pkg/thriftx"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/go-kratos/kratos/v2/transport"
+)
+
+var _ transport.Transporter = (*ThriftTransport)(nil)
+
+// ThriftTransport thrift 服务端 transport，请求头与响应头基于 THeader
+type ThriftTransport struct {
+	endpoint    string
+	operation   string
+	reqHeader   thriftx.HeaderCarrier
+	replyHeader replyHeader
+}
+
+// Kind returns the transport kind.
+func (tr *ThriftTransport) Kind() transport.Kind {
+	return thriftx.KindThrift
+}
+
+// Endpoint returns the transport endpoint.
+func (tr *ThriftTransport) Endpoint() string {
+	return tr.endpoint
+}
+
+// Operation returns the transport operation, e.g. GiftService.SendGift.
+func (tr *ThriftTransport) Operation() string {
+	return tr.operation
+}
+
+// RequestHeader returns the request header.
+func (tr *ThriftTransport) RequestHeader() transport.Header {
+	return tr.reqHeader
+}
+
+// ReplyHeader returns the reply header.
+func (tr *ThriftTransport) ReplyHeader() transport.Header {
+	return tr.replyHeader
+}
+
+// replyHeader 写入时同步设置到 THeader 响应头，非 THeader 协议时只保存在本地
+//
+// 响应头需要在处理函数写回结果之前设置。
+type replyHeader struct {
+	thriftx.HeaderCarrier
+	helper responseHeaders
+}
+
+// responseHeaders 连接的 THeader 响应头，开启 timeout 时为处理函数自己的 bufferedReplyHeader
+type responseHeaders interface {
+	SetHeader(key, value string)
+	ClearHeaders()
+}
+
+// responseHeadersFromContext 优先使用 timeoutProcessor 注入的 bufferedReplyHeader
+func responseHeadersFromContext(ctx context.Context) responseHeaders {
+	if h, ok := ctx.Value(bufferedReplyHeaderKey{}).(*bufferedReplyHeader); ok {
+		return h
+	}
+	helper, _ := thrift.GetResponseHelper(ctx)
+	return helper
+}
+
+// Set stores the key-value pair.
+func (h replyHeader) Set(key string, value string) {
+	h.HeaderCarrier.Set(key, value)
+	h.helper.SetHeader(key, h.HeaderCarrier.Get(key))
+}
+
+// Add append value to key-values pair.
+func (h replyHeader) Add(key string, value string) {
+	h.HeaderCarrier.Add(key, value)
+	h.helper.SetHeader(key, h.HeaderCarrier.Get(key))
+}
+
+// transportProcessor 为每次调用在 context 中注入服务端 transport
+func transportProcessor(endpoint string) thrift.ProcessorMiddleware {
+	return func(name string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		operation := strings.Replace(name, thrift.MULTIPLEXED_SEPARATOR, ".", 1)
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				reqHeader := make(thriftx.HeaderCarrier)
+				for _, key := range thrift.GetReadHeaderList(ctx) {
+					if value, ok := thrift.GetHeader(ctx, key); ok {
+						reqHeader[key] = value
+					}
+				}
+				// 同一连接上的响应头会一直保留，每次调用前清空
+				helper := responseHeadersFromContext(ctx)
+				helper.ClearHeaders()
+				tr := &ThriftTransport{
+					endpoint:    endpoint,
+					operation:   operation,
+					reqHeader:   reqHeader,
+					replyHeader: replyHeader{HeaderCarrier: make(thriftx.HeaderCarrier), helper: helper},
+				}
+				return next.Process(transport.NewServerContext(ctx, tr), seqID, in, out)
+			},
+		}
+	}
+}
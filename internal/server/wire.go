@@ -1,6 +1,10 @@
 package server
 
-import "github.com/google/wire"
+import (
+	"aboveThriftRPC/internal/pkg/health"
+
+	"github.com/google/wire"
+)
 
 // ProviderSet is server providers.
-var ProviderSet = wire.NewSet(NewThriftServer)
+var ProviderSet = wire.NewSet(health.NewServer, NewThriftServerOptions, NewThriftServer, NewHTTPServer, NewGRPCServer, NewRegistrar)
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"aboveThriftRPC/api/gen-go/gift_service"
+	giftv1 "aboveThriftRPC/api/gift_service/v1"
+)
+
+// GRPCGiftService 礼物服务的 gRPC 实现，转换类型后调用 thrift 实现，两者共用同一个 biz
+type GRPCGiftService struct {
+	giftv1.UnimplementedGiftServiceServer
+	gift gift_service.GiftService
+}
+
+// 显示构造
+func NewGRPCGiftService(gift gift_service.GiftService) giftv1.GiftServiceServer {
+	return &GRPCGiftService{
+		gift: gift,
+	}
+}
+
+func (s *GRPCGiftService) SendGift(ctx context.Context, req *giftv1.SendGiftRequest) (*giftv1.Gift, error) {
+	gift, err := s.gift.SendGift(ctx, req.GetSenderId(), req.GetReceiverId(), req.GetPrice(), giftv1.GiftTypeToThrift(req.GetGiftType()), req.GetQuantity())
+	if err != nil {
+		return nil, err
+	}
+	return giftv1.GiftFromThrift(gift), nil
+}
+
+func (s *GRPCGiftService) GetTop10Senders(ctx context.Context, req *giftv1.GetTop10SendersRequest) (*giftv1.GetTop10SendersResponse, error) {
+	senders, err := s.gift.GetTop10Senders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &giftv1.GetTop10SendersResponse{Result: senders}, nil
+}
+
+func (s *GRPCGiftService) GetSendersInLastWeek(ctx context.Context, req *giftv1.GetSendersInLastWeekRequest) (*giftv1.GetSendersInLastWeekResponse, error) {
+	senders, err := s.gift.GetSendersInLastWeek(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &giftv1.GetSendersInLastWeekResponse{Result: senders}, nil
+}
+
+func (s *GRPCGiftService) GetGiftsBySender(ctx context.Context, req *giftv1.GetGiftsBySenderRequest) (*giftv1.GetGiftsBySenderResponse, error) {
+	gifts, err := s.gift.GetGiftsBySender(ctx, req.GetSenderId())
+	if err != nil {
+		return nil, err
+	}
+	resp := &giftv1.GetGiftsBySenderResponse{Result: make([]*giftv1.Gift, 0, len(gifts))}
+	for _, gift := range gifts {
+		resp.Result = append(resp.Result, giftv1.GiftFromThrift(gift))
+	}
+	return resp, nil
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+
+	"aboveThriftRPC/api/gen-go/user_service"
+	userv1 "aboveThriftRPC/api/user_service/v1"
+)
+
+// GRPCUserService 用户服务的 gRPC 实现，转换类型后调用 thrift 实现，两者共用同一个 biz
+type GRPCUserService struct {
+	userv1.UnimplementedUserServiceServer
+	user user_service.UserService
+}
+
+// 显示构造
+func NewGRPCUserService(user user_service.UserService) userv1.UserServiceServer {
+	return &GRPCUserService{
+		user: user,
+	}
+}
+
+func (s *GRPCUserService) EchoData(ctx context.Context, req *userv1.EchoDataRequest) (*userv1.EchoResponse, error) {
+	resp, err := s.user.EchoData(ctx, req.GetClientData(), userv1.UserToThrift(req.GetUser()))
+	if err != nil {
+		return nil, err
+	}
+	return userv1.EchoResponseFromThrift(resp), nil
+}